@@ -9,33 +9,56 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vito-ai/go-sdk/auth/credentials"
 	"github.com/vito-ai/go-sdk/auth/option"
 )
 
+var (
+	// ErrInvalidCredentials is returned when the client id or secret is missing or rejected.
+	ErrInvalidCredentials = errors.New("auth: invalid credentials")
+	// ErrAuthUnreachable is returned when the authentication server cannot be reached.
+	ErrAuthUnreachable = errors.New("auth: authentication server unreachable")
+	// ErrInvalidTokenURL is returned when the token URL is malformed.
+	ErrInvalidTokenURL = errors.New("auth: invalid token url")
+)
+
 // TokenProvider Interface
 type TokenProvider interface {
 	Token(context.Context) (*ReturnZeroToken, error)
 }
 
 // Default Token Provider for RTZR
+// It is safe for concurrent use; concurrent callers share a single token fetch.
 type tokenProviderRTZR struct {
+	// mu guards token and fetching; it is not held during a fetch.
+	mu           sync.Mutex
 	token        *ReturnZeroToken
+	fetching     *tokenFetch
 	Client       *http.Client
 	clientId     string
 	clientSecret string
 	TokenURL     string
+
+	// retry policy while the authentication server is unreachable
+	maxAttempts  int
+	retryBackoff time.Duration
 }
 
 func NewRTZRTokenProvider(opt *option.ClientOption) (TokenProvider, error) {
-	creds := credentials.GetDefaultClientCreds()
+	creds, err := defaultCreds(opt)
+	if err != nil {
+		return nil, err
+	}
 	tp := &tokenProviderRTZR{
 		clientId:     opt.GetClientId(creds.ClientId),
 		clientSecret: opt.GetClientSecret(creds.ClientSecret),
 		TokenURL:     opt.GetTokenURL(),
-		Client:       http.DefaultClient,
+		Client:       &http.Client{Transport: opt.GetTransport()},
+		maxAttempts:  opt.GetAuthMaxAttempts(),
+		retryBackoff: opt.GetAuthRetryBackoff(),
 	}
 
 	if err := tp.validate(); err != nil {
@@ -45,27 +68,142 @@ func NewRTZRTokenProvider(opt *option.ClientOption) (TokenProvider, error) {
 	return tp, nil
 }
 
+// defaultCreds returns the credentials used when they are not set in opt:
+// the environment variables first, then opt.CredentialsFile. The file is not read when
+// the environment variables or opt already provide both values.
+func defaultCreds(opt *option.ClientOption) (*credentials.ReturnZeroCredentials, error) {
+	creds := credentials.GetDefaultClientCreds()
+	if opt.ClientId != "" {
+		creds.ClientId = opt.ClientId
+	}
+	if opt.ClientSecret != "" {
+		creds.ClientSecret = opt.ClientSecret
+	}
+	if opt.CredentialsFile == "" || (creds.ClientId != "" && creds.ClientSecret != "") {
+		return creds, nil
+	}
+	fileCreds, err := credentials.GetClientCredsFromFile(opt.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	if creds.ClientId == "" {
+		creds.ClientId = fileCreds.ClientId
+	}
+	if creds.ClientSecret == "" {
+		creds.ClientSecret = fileCreds.ClientSecret
+	}
+	return creds, nil
+}
+
 func (o *tokenProviderRTZR) validate() error {
 	if o == nil {
 		return errors.New("auth : options must be provided")
 	}
 	if o.clientId == "" {
-		return errors.New("auth: RTZR_CLIENT_ID must be provided")
+		return fmt.Errorf("%w: RTZR_CLIENT_ID must be provided", ErrInvalidCredentials)
 	}
 	if o.clientSecret == "" {
-		return errors.New("auth: RTZR_CLIENT_SECRET must be provided")
+		return fmt.Errorf("%w: RTZR_CLIENT_SECRET must be provided", ErrInvalidCredentials)
 	}
 	if o.TokenURL == "" {
-		return errors.New("auth: TokenURL must be provided")
+		return fmt.Errorf("%w: TokenURL must be provided", ErrInvalidTokenURL)
+	}
+	u, err := url.Parse(o.TokenURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTokenURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q must be an absolute http(s) url", ErrInvalidTokenURL, o.TokenURL)
 	}
 	return nil
 }
 
+// Token returns the cached token, or fetches a new one when it is missing or expired.
+// Until a token has been fetched once, fetches failing with ErrAuthUnreachable are retried with
+// exponential backoff up to maxAttempts times, and the last error is returned once the attempts
+// are exhausted. Refreshing an expired token is attempted once, so that an outage does not stall
+// every API call for the whole backoff.
+//
+// Concurrent callers share a single fetch, made with the context of the caller which started it.
+// The others wait for it, or for their own ctx, and start a new fetch if it was cancelled.
 func (tp *tokenProviderRTZR) Token(ctx context.Context) (*ReturnZeroToken, error) {
-	if tp.token.isValidWithExpiry() {
-		return tp.token, nil
+	for {
+		tp.mu.Lock()
+		if tp.token.isValidWithExpiry() {
+			token := tp.token
+			tp.mu.Unlock()
+			return token, nil
+		}
+		f := tp.fetching
+		if f == nil {
+			f = &tokenFetch{done: make(chan struct{}), initial: tp.token == nil}
+			tp.fetching = f
+			tp.mu.Unlock()
+			tp.fetch(ctx, f)
+			return f.token, f.err
+		}
+		tp.mu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// the fetch was abandoned by the caller which started it, not failed
+		if errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded) {
+			continue
+		}
+		return f.token, f.err
 	}
+}
+
+// tokenFetch is a token fetch shared by concurrent callers of Token.
+type tokenFetch struct {
+	done chan struct{}
+	// initial is set when no token has been fetched yet
+	initial bool
+	token   *ReturnZeroToken
+	err     error
+}
+
+// fetch fetches a token into f, caches it and wakes up the callers waiting for f.
+func (tp *tokenProviderRTZR) fetch(ctx context.Context, f *tokenFetch) {
+	attempts := 1
+	if f.initial {
+		attempts = tp.maxAttempts
+	}
+	f.token, f.err = tp.fetchWithRetry(ctx, attempts)
+
+	tp.mu.Lock()
+	if f.err == nil {
+		tp.token = f.token
+	}
+	tp.fetching = nil
+	tp.mu.Unlock()
+	close(f.done)
+}
+
+func (tp *tokenProviderRTZR) fetchWithRetry(ctx context.Context, maxAttempts int) (*ReturnZeroToken, error) {
+	backoff := tp.retryBackoff
+	for attempt := 1; ; attempt++ {
+		token, err := tp.fetchToken(ctx)
+		if err == nil {
+			return token, nil
+		}
+		if !errors.Is(err, ErrAuthUnreachable) || attempt >= maxAttempts {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
 
+func (tp *tokenProviderRTZR) fetchToken(ctx context.Context) (*ReturnZeroToken, error) {
 	formData := url.Values{}
 	formData.Set("client_id", tp.clientId)
 	formData.Set("client_secret", tp.clientSecret)
@@ -80,11 +218,20 @@ func (tp *tokenProviderRTZR) Token(ctx context.Context) (*ReturnZeroToken, error
 
 	resp, err := tp.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error when making authentication request: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("%w: error when making authentication request: %w", ErrAuthUnreachable, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w: error response from authentication server: %s", ErrInvalidCredentials, resp.Status)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: error response from authentication server: %s", ErrAuthUnreachable, resp.Status)
+	default:
 		return nil, fmt.Errorf("error response from authentication server: %s", resp.Status)
 	}
 
@@ -105,6 +252,5 @@ func (tp *tokenProviderRTZR) Token(ctx context.Context) (*ReturnZeroToken, error
 		return nil, errors.New("unmarshalled response has invalid expire_at timestamp")
 	}
 
-	tp.token = result
 	return result, nil
 }
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vito-ai/go-sdk/auth/option"
+)
+
+// newAuthServer returns a fake authentication server answering every request with handler.
+func newAuthServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func tokenHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, `{"access_token":"token","expire_at":%d}`, time.Now().Add(time.Hour).Unix())
+}
+
+func newTestProvider(t *testing.T, opt *option.ClientOption) (TokenProvider, error) {
+	t.Helper()
+	// the environment must not fill in missing credentials
+	t.Setenv("RTZR_CLIENT_ID", "")
+	t.Setenv("RTZR_CLIENT_SECRET", "")
+	return NewRTZRTokenProvider(opt)
+}
+
+func TestNewRTZRTokenProviderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  *option.ClientOption
+		want error
+	}{
+		{"missing id", &option.ClientOption{ClientSecret: "secret"}, ErrInvalidCredentials},
+		{"missing secret", &option.ClientOption{ClientId: "id"}, ErrInvalidCredentials},
+		{"relative token url", &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: "/v1/authenticate"}, ErrInvalidTokenURL},
+		{"malformed token url", &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: "http://[::1"}, ErrInvalidTokenURL},
+		{"unsupported scheme", &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: "ftp://example.com/token"}, ErrInvalidTokenURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestProvider(t, tt.opt)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("NewRTZRTokenProvider() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenErrors(t *testing.T) {
+	closed := httptest.NewServer(http.HandlerFunc(tokenHandler))
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		tokenURL string
+		want     error
+	}{
+		{"rejected credentials", newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}).URL, ErrInvalidCredentials},
+		{"forbidden", newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}).URL, ErrInvalidCredentials},
+		{"server error", newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}).URL, ErrAuthUnreachable},
+		{"connection refused", closed.URL, ErrAuthUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := newTestProvider(t, &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: tt.tokenURL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tp.Token(context.Background()); !errors.Is(err, tt.want) {
+				t.Fatalf("Token() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenCached(t *testing.T) {
+	fetches := 0
+	srv := newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		tokenHandler(w, r)
+	})
+	tp, err := newTestProvider(t, &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		token, err := tp.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.AccessToken != "token" {
+			t.Fatalf("AccessToken = %q, want %q", token.AccessToken, "token")
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("fetched %d tokens, want 1", fetches)
+	}
+}
+
+func TestTokenWaitersHonourTheirContext(t *testing.T) {
+	var fetches atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	srv := newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		tokenHandler(w, r)
+	})
+	tp, err := newTestProvider(t, &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := tp.Token(context.Background())
+		first <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tp.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("waiting Token() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tp.Token(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	close(release)
+	wg.Wait()
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("fetched %d tokens, want 1", n)
+	}
+}
+
+func TestCredentialsPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(file, []byte(`{"client_id":"file-id","client_secret":"file-secret"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name             string
+		envId, envSecret string
+		opt              *option.ClientOption
+		wantId, wantSec  string
+	}{
+		{"explicit over env", "env-id", "env-secret", &option.ClientOption{ClientId: "id", ClientSecret: "secret"}, "id", "secret"},
+		{"env over file", "env-id", "env-secret", &option.ClientOption{CredentialsFile: file}, "env-id", "env-secret"},
+		{"file fills the rest", "", "env-secret", &option.ClientOption{CredentialsFile: file}, "file-id", "env-secret"},
+		{"explicit skips missing file", "", "", &option.ClientOption{ClientId: "id", ClientSecret: "secret", CredentialsFile: "missing.json"}, "id", "secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RTZR_CLIENT_ID", tt.envId)
+			t.Setenv("RTZR_CLIENT_SECRET", tt.envSecret)
+			tp, err := NewRTZRTokenProvider(tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rtzr := tp.(*tokenProviderRTZR)
+			if rtzr.clientId != tt.wantId || rtzr.clientSecret != tt.wantSec {
+				t.Fatalf("credentials = %q/%q, want %q/%q", rtzr.clientId, rtzr.clientSecret, tt.wantId, tt.wantSec)
+			}
+		})
+	}
+}
+
+func TestTokenRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int32
+		expired     bool
+		wantFetches int32
+		wantErr     error
+		minElapsed  time.Duration
+	}{
+		{"recovers", 2, false, 3, nil, 30 * time.Millisecond},
+		{"exhausted", 5, false, 3, ErrAuthUnreachable, 30 * time.Millisecond},
+		{"refresh is not retried", 5, true, 1, ErrAuthUnreachable, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetches atomic.Int32
+			srv := newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if fetches.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				tokenHandler(w, r)
+			})
+			tp, err := newTestProvider(t, &option.ClientOption{
+				ClientId: "id", ClientSecret: "secret", TokenURL: srv.URL,
+				AuthMaxAttempts: 3, AuthRetryBackoff: 10 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.expired {
+				tp.(*tokenProviderRTZR).token = &ReturnZeroToken{AccessToken: "old", ExpireAt: time.Now().Add(-time.Minute).Unix()}
+			}
+
+			start := time.Now()
+			_, err = tp.Token(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Token() error = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("Token() returned after %v, want backoff of at least %v", elapsed, tt.minElapsed)
+			}
+			if n := fetches.Load(); n != tt.wantFetches {
+				t.Errorf("fetched %d times, want %d", n, tt.wantFetches)
+			}
+		})
+	}
+}
+
+func TestTokenRetryCancelled(t *testing.T) {
+	srv := newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	tp, err := newTestProvider(t, &option.ClientOption{
+		ClientId: "id", ClientSecret: "secret", TokenURL: srv.URL,
+		AuthMaxAttempts: 5, AuthRetryBackoff: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tp.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Token() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
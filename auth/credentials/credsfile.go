@@ -1,6 +1,8 @@
 package credentials
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 )
 
@@ -10,8 +12,8 @@ const (
 )
 
 type ReturnZeroCredentials struct {
-	ClientId     string
-	ClientSecret string
+	ClientId     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
 }
 
 func getVaraiableFromEnv(override string) string {
@@ -28,3 +30,18 @@ func GetDefaultClientCreds() *ReturnZeroCredentials {
 		ClientSecret: clientSecret,
 	}
 }
+
+// GetClientCredsFromFile reads credentials from a JSON file of the form
+//
+//	{"client_id": "YOUR_CLIENT_ID", "client_secret": "YOUR_CLIENT_SECRET"}
+func GetClientCredsFromFile(path string) (*ReturnZeroCredentials, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("credentials: cannot read credentials file: %w", err)
+	}
+	creds := &ReturnZeroCredentials{}
+	if err := json.Unmarshal(b, creds); err != nil {
+		return nil, fmt.Errorf("credentials: invalid credentials file %s: %w", path, err)
+	}
+	return creds, nil
+}
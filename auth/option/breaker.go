@@ -0,0 +1,51 @@
+package option
+
+import "time"
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests without sending them.
+	CircuitOpen
+	// CircuitHalfOpen lets a single request through to test whether the server recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig controls the circuit breaker guarding the server.
+// After Threshold consecutive failed requests (transport errors and 5xx responses) the circuit opens
+// and requests fail immediately. After Cooldown it half-opens: one request is let through, and the
+// circuit closes if it succeeds or opens again if it fails.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive failures which opens the circuit. Defaults to 5.
+	Threshold int
+	// Cooldown is how long the circuit stays open before half-opening. Defaults to 30s.
+	Cooldown time.Duration
+	// OnStateChange, when set, is called on every state transition. It must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// WithDefaults returns cb with unset fields replaced by their defaults.
+func (cb CircuitBreakerConfig) WithDefaults() CircuitBreakerConfig {
+	if cb.Threshold <= 0 {
+		cb.Threshold = 5
+	}
+	if cb.Cooldown <= 0 {
+		cb.Cooldown = 30 * time.Second
+	}
+	return cb
+}
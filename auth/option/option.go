@@ -1,20 +1,219 @@
 package option
 
+import (
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vito-ai/go-sdk/auth/credentials"
+)
+
+const (
+	// EnvRestEndpoint overrides the default REST endpoint when Endpoint is not set.
+	EnvRestEndpoint = "RTZR_REST_ENDPOINT"
+	// EnvStreamingEndpoint overrides the default streaming endpoint when Endpoint is not set.
+	EnvStreamingEndpoint = "RTZR_STREAMING_ENDPOINT"
+)
+
 type ClientOption struct {
 	ClientId     string
 	ClientSecret string
-	Endpoint     string
-	TokenURL     string
+	// Endpoint is resolved in order: this field, then the RTZR_REST_ENDPOINT (REST) or
+	// RTZR_STREAMING_ENDPOINT (streaming) environment variable, then the built-in default.
+	Endpoint string
+	TokenURL string
+
+	// CredentialsFile is a JSON file holding client_id and client_secret.
+	// Credentials are resolved in order: ClientId/ClientSecret, then the
+	// RTZR_CLIENT_ID/RTZR_CLIENT_SECRET environment variables, then this file.
+	CredentialsFile string
+
+	// AuthMaxAttempts bounds how many times the first token fetch is attempted while the
+	// authentication server is unreachable. Refreshing an expired token is not retried. Defaults to 1 (no retry).
+	AuthMaxAttempts int
+	// AuthRetryBackoff is the delay before the first token fetch retry; it doubles on every retry.
+	// Defaults to 500ms.
+	AuthRetryBackoff time.Duration
+
+	// CopyBufferSize is the buffer size used to copy audio into the upload body.
+	// Larger buffers trade memory for throughput on fast links. Defaults to 32KB.
+	CopyBufferSize int
+
+	// InMemoryUploadThreshold is the largest in-memory Content (in bytes) whose upload body is
+	// built synchronously in a reused buffer, instead of being streamed through a pipe.
+	// Defaults to 100KB; a negative value always streams.
+	InMemoryUploadThreshold int
+
+	// RawUploadThreshold, when positive, sends audio of a known size (Content or FilePath) larger than
+	// this many bytes as the raw request body, with the config JSON in the "config" query parameter,
+	// instead of as a multipart form. It saves the multipart framing on very large uploads, but the
+	// config is then limited by URL length limits, and the RTZR API itself only accepts multipart
+	// forms: enable it only for gateways or servers which accept raw bodies. Zero disables it.
+	RawUploadThreshold int64
+
+	// BatchConcurrency bounds how many requests RecognizeBatch, and RecognizeLarge for the parts of
+	// the audio, upload and wait for at once. Defaults to 4; a negative value removes the limit.
+	BatchConcurrency int
+
+	// QueryParams are appended to every submit request.
+	// They allow toggling server features which are not modeled by the SDK yet.
+	QueryParams url.Values
+
+	// PollRetry is the number of consecutive transient failures (timeouts, 5xx, 429)
+	// tolerated while polling for a result. Zero aborts polling on the first failure.
+	PollRetry int
+
+	// RetryPolicy, when set, decides which failed result polls count as transient (see PollRetry),
+	// replacing the default of transport errors other than rejected credentials, 5xx and 429
+	// responses. It gets either the response, whose status code and headers may be inspected but whose
+	// body has already been consumed, or the transport error with a nil response. Cancellation of the
+	// request context is never retried.
+	RetryPolicy func(resp *http.Response, err error) bool
+
+	// HedgeDelay, when positive, sends a second identical request for a result poll which has not been
+	// answered within HedgeDelay, and uses whichever response arrives first, trimming tail latency at the
+	// cost of extra load. It only applies to result and status GETs, which are idempotent; submissions are
+	// never hedged. Zero disables hedging.
+	HedgeDelay time.Duration
+
+	// FailedRetries is how many times Recognize resubmits a job which the server reported as failed,
+	// which sometimes happens for transient reasons. Failures whose code is in PermanentFailureCodes,
+	// and audio from a Reader which cannot be read again, are not resubmitted. Zero disables resubmission.
+	FailedRetries int
+	// PermanentFailureCodes are failure codes reported by the server, such as an invalid audio format,
+	// for which resubmitting cannot help.
+	PermanentFailureCodes []string
+
+	// CircuitBreaker, when set, fails requests with speech.ErrCircuitOpen while the server keeps failing,
+	// instead of sending them. Clients derived with WithCredentials share the breaker.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// ResultEndpoint is the endpoint results are fetched and deleted from, for deployments serving them
+	// from another host than submissions. ResultPathTemplate applies to it. Defaults to the REST endpoint.
+	ResultEndpoint string
+
+	// SubmitMethod is the HTTP method of submit requests, for gateways expecting e.g. PUT.
+	// It must be POST, PUT or PATCH. Defaults to POST.
+	SubmitMethod string
+
+	// SubmitPath replaces the path of the REST endpoint for submit requests, e.g. "/stt/v1/transcribe".
+	SubmitPath string
+	// ResultPathTemplate is the path used to fetch a result, where "{id}" is replaced
+	// with the result id, e.g. "/stt/v1/transcribe/{id}". Defaults to the endpoint followed by "/{id}".
+	ResultPathTemplate string
+
+	// ResultCacheSize is the number of completed results kept in memory, so that
+	// ReceiveResult for the same id does not fetch them again. Zero disables the cache.
+	ResultCacheSize int
+
+	// AcceptLanguage is sent as the Accept-Language header of REST requests. Defaults to "en".
+	// It only affects messages written by the server, such as error descriptions.
+	AcceptLanguage string
+
+	// MaxResponseBytes caps the size of a response body the client reads. Defaults to 64MB.
+	MaxResponseBytes int64
+
+	// RedactErrorBodies leaves server response bodies, which may echo request details, out of the
+	// messages of the errors returned by the client, so that logging an error does not log them.
+	// The body of a speech.APIError is still available in its Body field. Defaults to false,
+	// which includes the bodies.
+	RedactErrorBodies bool
+
+	// VerifyResults checks result bodies against their Content-Length and, when the server sends one,
+	// Content-MD5 header, failing with speech.ErrCorruptResponse on a mismatch instead of returning
+	// a truncated or altered result. It costs hashing every result body.
+	VerifyResults bool
+
+	// DedupeSubmissions makes a submission whose config, query parameters and audio are identical to
+	// those of a job still in flight on the same client return the id of that job instead of creating
+	// a new one, so that an accidental resubmission is not billed twice. Jobs leave the in-flight set
+	// once their completion or failure is observed, they are deleted, or they are passed to Forget.
+	// Only submissions through a single client instance are deduplicated, not those of other clients
+	// or processes. It costs reading and hashing the audio before every upload; audio from a Reader
+	// which cannot seek is never deduplicated.
+	DedupeSubmissions bool
+
+	// Polling controls the interval between result polls.
+	Polling PollingConfig
+
+	// Transport is the base RoundTripper for HTTP requests. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// ForceHTTP1 disables HTTP/2 for REST requests, for proxies and load balancers which mishandle it.
+	// It is ignored when Transport is set.
+	ForceHTTP1 bool
+	// MinTLSVersion is the minimum TLS version accepted, e.g. tls.VersionTLS12.
+	// Zero keeps the crypto/tls default. For REST requests it is ignored when Transport is set.
+	MinTLSVersion uint16
+
+	// DialTimeout bounds how long connecting to the REST server (DNS lookup and TCP connect) may take.
+	// Defaults to 30s, as http.DefaultTransport. It is ignored when Transport is set.
+	DialTimeout time.Duration
+	// ResponseHeaderTimeout bounds how long to wait for the response headers once a request,
+	// including the uploaded audio, has been written. Zero means no limit besides the request context.
+	// It is ignored when Transport is set.
+	ResponseHeaderTimeout time.Duration
+
+	// StreamingKeepalive is the interval of the HTTP/2 keep-alive pings the streaming client sends
+	// while the connection is idle, including during silences of an open stream, so that proxies and
+	// load balancers do not drop it. Zero, the default, sends no pings, and the connection may be
+	// dropped by intermediaries after their idle timeout; pick an interval below it. Intervals under
+	// 10s are raised to 10s, and servers may close connections which ping more often than they allow.
+	// The pings keep the connection, not the recognition session: the server's own limit on how long
+	// a stream may go without audio still applies.
+	StreamingKeepalive time.Duration
+	// StreamingKeepaliveTimeout is how long the streaming client waits for a ping acknowledgment
+	// before closing the connection as dead. Defaults to 20s.
+	StreamingKeepaliveTimeout time.Duration
+
+	// Logger receives the SDK's logs. Defaults to slog.Default().
+	Logger *slog.Logger
+
+	// LogPolls logs every result poll attempt to Logger at slog.LevelInfo; see speech.WithPollLogging
+	// to enable it for single calls instead.
+	LogPolls bool
+
+	// Debug dumps every HTTP request and response to Logger. The dumps are logged at slog.LevelInfo,
+	// since setting Debug already opts in to them, so they show up with the default logger.
+	// The Authorization header, form-encoded bodies (which hold the client secret) and their responses
+	// (which hold the access token) are redacted.
+	Debug bool
+	// DebugBodyLimit caps how many bytes of each body are dumped. Defaults to 1KB.
+	DebugBodyLimit int
 }
 
 func DefaultClientOption() *ClientOption {
 	return &ClientOption{}
 }
 
+// FromEnv returns a ClientOption with the credentials read from
+// the RTZR_CLIENT_ID and RTZR_CLIENT_SECRET environment variables.
+func FromEnv() *ClientOption {
+	creds := credentials.GetDefaultClientCreds()
+	return &ClientOption{
+		ClientId:     creds.ClientId,
+		ClientSecret: creds.ClientSecret,
+	}
+}
+
+// FromFile returns a ClientOption reading the credentials from the JSON file at path.
+// The file is read when the client is created, and only for values not set explicitly or in the environment.
+func FromFile(path string) *ClientOption {
+	return &ClientOption{CredentialsFile: path}
+}
+
 func (opt *ClientOption) GetRestEndpoint() string {
 	if opt.Endpoint != "" {
 		return opt.Endpoint
 	}
+	if endpoint := os.Getenv(EnvRestEndpoint); endpoint != "" {
+		return endpoint
+	}
 	return "https://openapi.vito.ai/v1/transcribe"
 }
 
@@ -22,9 +221,19 @@ func (opt *ClientOption) GetStreamingEndpoint() string {
 	if opt.Endpoint != "" {
 		return opt.Endpoint
 	}
+	if endpoint := os.Getenv(EnvStreamingEndpoint); endpoint != "" {
+		return endpoint
+	}
 	return "grpc-openapi.vito.ai:443"
 }
 
+func (opt *ClientOption) GetSubmitMethod() string {
+	if opt.SubmitMethod != "" {
+		return opt.SubmitMethod
+	}
+	return http.MethodPost
+}
+
 func (opt *ClientOption) GetTokenURL() string {
 	if opt.TokenURL != "" {
 		return opt.TokenURL
@@ -32,16 +241,153 @@ func (opt *ClientOption) GetTokenURL() string {
 	return "https://openapi.vito.ai/v1/authenticate"
 }
 
-func (opt *ClientOption) GetClientId(override string) string {
-	if override != "" {
-		return override
+// GetClientId returns ClientId, or fallback when it is not set.
+//
+// Before FromEnv and FromFile were added, the RTZR_CLIENT_ID environment variable overrode ClientId.
+// Explicitly set values now take precedence over the environment.
+func (opt *ClientOption) GetClientId(fallback string) string {
+	if opt.ClientId != "" {
+		return opt.ClientId
+	}
+	return fallback
+}
+
+// GetClientSecret returns ClientSecret, or fallback when it is not set, with the same precedence as GetClientId.
+func (opt *ClientOption) GetClientSecret(fallback string) string {
+	if opt.ClientSecret != "" {
+		return opt.ClientSecret
+	}
+	return fallback
+}
+
+func (opt *ClientOption) GetStreamingKeepaliveTimeout() time.Duration {
+	if opt.StreamingKeepaliveTimeout > 0 {
+		return opt.StreamingKeepaliveTimeout
+	}
+	return 20 * time.Second
+}
+
+func (opt *ClientOption) GetCopyBufferSize() int {
+	if opt.CopyBufferSize > 0 {
+		return opt.CopyBufferSize
+	}
+	return 32 * 1024
+}
+
+// transportKey holds the options a transport built by GetTransport depends on.
+type transportKey struct {
+	forceHTTP1            bool
+	minTLSVersion         uint16
+	dialTimeout           time.Duration
+	responseHeaderTimeout time.Duration
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[transportKey]*http.Transport{}
+)
+
+// GetTransport returns Transport, or http.DefaultTransport adjusted by ForceHTTP1, MinTLSVersion,
+// DialTimeout and ResponseHeaderTimeout. Adjusted transports are built once per combination of those
+// options and then reused, so that every client with the same options shares one connection pool.
+func (opt *ClientOption) GetTransport() http.RoundTripper {
+	if opt.Transport != nil {
+		return opt.Transport
+	}
+	if !opt.ForceHTTP1 && opt.MinTLSVersion == 0 && opt.DialTimeout == 0 && opt.ResponseHeaderTimeout == 0 {
+		return http.DefaultTransport
+	}
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return http.DefaultTransport
+	}
+
+	key := transportKey{opt.ForceHTTP1, opt.MinTLSVersion, opt.DialTimeout, opt.ResponseHeaderTimeout}
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[key]; ok {
+		return t
+	}
+	t := base.Clone()
+	if opt.ForceHTTP1 {
+		t.ForceAttemptHTTP2 = false
+		// A non-nil, empty map disables HTTP/2 negotiation over TLS.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if opt.MinTLSVersion != 0 {
+		t.TLSClientConfig = opt.TLSConfig()
+	}
+	if opt.DialTimeout > 0 {
+		t.DialContext = (&net.Dialer{Timeout: opt.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if opt.ResponseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = opt.ResponseHeaderTimeout
+	}
+	transports[key] = t
+	return t
+}
+
+// TLSConfig returns the TLS configuration honoring MinTLSVersion.
+func (opt *ClientOption) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: opt.MinTLSVersion}
+}
+
+func (opt *ClientOption) GetLogger() *slog.Logger {
+	if opt.Logger != nil {
+		return opt.Logger
+	}
+	return slog.Default()
+}
+
+func (opt *ClientOption) GetDebugBodyLimit() int {
+	if opt.DebugBodyLimit > 0 {
+		return opt.DebugBodyLimit
+	}
+	return 1024
+}
+
+func (opt *ClientOption) GetAuthMaxAttempts() int {
+	if opt.AuthMaxAttempts > 0 {
+		return opt.AuthMaxAttempts
+	}
+	return 1
+}
+
+func (opt *ClientOption) GetAuthRetryBackoff() time.Duration {
+	if opt.AuthRetryBackoff > 0 {
+		return opt.AuthRetryBackoff
+	}
+	return 500 * time.Millisecond
+}
+
+func (opt *ClientOption) GetPolling() PollingConfig {
+	return opt.Polling.WithDefaults()
+}
+
+func (opt *ClientOption) GetMaxResponseBytes() int64 {
+	if opt.MaxResponseBytes > 0 {
+		return opt.MaxResponseBytes
+	}
+	return 64 << 20
+}
+
+func (opt *ClientOption) GetBatchConcurrency() int {
+	if opt.BatchConcurrency != 0 {
+		return opt.BatchConcurrency
+	}
+	return 4
+}
+
+func (opt *ClientOption) GetInMemoryUploadThreshold() int {
+	if opt.InMemoryUploadThreshold != 0 {
+		return opt.InMemoryUploadThreshold
 	}
-	return opt.ClientId
+	return 100 * 1024
 }
 
-func (opt *ClientOption) GetClientSecret(override string) string {
-	if override != "" {
-		return override
+func (opt *ClientOption) GetAcceptLanguage() string {
+	if opt.AcceptLanguage != "" {
+		return opt.AcceptLanguage
 	}
-	return opt.ClientSecret
+	return "en"
 }
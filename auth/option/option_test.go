@@ -0,0 +1,18 @@
+package option
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTransportReused(t *testing.T) {
+	a := &ClientOption{ForceHTTP1: true, DialTimeout: time.Second}
+	b := &ClientOption{ForceHTTP1: true, DialTimeout: time.Second}
+	if a.GetTransport() != a.GetTransport() || a.GetTransport() != b.GetTransport() {
+		t.Fatal("GetTransport() built a new transport for the same options")
+	}
+	c := &ClientOption{ForceHTTP1: true, DialTimeout: 2 * time.Second}
+	if a.GetTransport() == c.GetTransport() {
+		t.Fatal("GetTransport() shared a transport between different options")
+	}
+}
@@ -0,0 +1,50 @@
+package option
+
+import "time"
+
+// PollingConfig controls how often the result of a job is polled.
+// The first poll happens right after submission, the second Interval later, and every following delay
+// is the previous one multiplied by Multiplier, capped at MaxInterval.
+//
+// When a poll response carries a Retry-After header, in seconds or as an HTTP date, the next poll
+// waits that long instead, whatever MaxInterval is but at most MaxRetryAfter; the following delays
+// are unaffected.
+type PollingConfig struct {
+	// Interval is the initial delay between polls. Defaults to 4s.
+	Interval time.Duration
+	// Multiplier grows the delay after every poll. Defaults to 1 (fixed interval).
+	Multiplier float64
+	// MaxInterval caps the delay between polls. Zero means no cap.
+	MaxInterval time.Duration
+	// MaxRetryAfter caps the delay a Retry-After header may ask for, so that a bogus header cannot
+	// stall polling. Defaults to 1 minute.
+	MaxRetryAfter time.Duration
+}
+
+// WithDefaults returns pc with unset fields replaced by their defaults.
+func (pc PollingConfig) WithDefaults() PollingConfig {
+	if pc.Interval <= 0 {
+		pc.Interval = 4 * time.Second
+	}
+	if pc.Multiplier < 1 {
+		pc.Multiplier = 1
+	}
+	if pc.MaxRetryAfter <= 0 {
+		pc.MaxRetryAfter = time.Minute
+	}
+	return pc
+}
+
+// Next returns the delay following delay.
+func (pc PollingConfig) Next(delay time.Duration) time.Duration {
+	next := time.Duration(float64(delay) * pc.Multiplier)
+	if pc.MaxInterval > 0 && next > pc.MaxInterval {
+		next = pc.MaxInterval
+	}
+	return next
+}
+
+// RetryAfter returns the delay before the next poll when the server hinted retryAfter.
+func (pc PollingConfig) RetryAfter(retryAfter time.Duration) time.Duration {
+	return min(retryAfter, pc.MaxRetryAfter)
+}
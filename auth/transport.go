@@ -12,20 +12,17 @@ type authTransport struct {
 }
 
 func NewAuthClient(cliopts *option.ClientOption) (*http.Client, error) {
-	tp, err := newAuthTransport(http.DefaultTransport, cliopts)
+	tp, err := NewRTZRTokenProvider(cliopts)
 	if err != nil {
 		return nil, err
 	}
-	httpClient := &http.Client{Transport: tp}
-	return httpClient, nil
+	return NewAuthClientWithTokenProvider(cliopts, tp), nil
 }
 
-func newAuthTransport(t http.RoundTripper, cliopts *option.ClientOption) (http.RoundTripper, error) {
-	tp, err := NewRTZRTokenProvider(cliopts)
-	if err != nil {
-		return nil, err
-	}
-	return &authTransport{transport: t, tokenProvider: tp}, nil
+// NewAuthClientWithTokenProvider returns a client authorizing requests with tokens from tp,
+// so that the caller can share tp, e.g. to fetch a token ahead of the first request.
+func NewAuthClientWithTokenProvider(cliopts *option.ClientOption, tp TokenProvider) *http.Client {
+	return &http.Client{Transport: &authTransport{transport: cliopts.GetTransport(), tokenProvider: tp}}
 }
 
 func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
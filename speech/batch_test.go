@@ -0,0 +1,164 @@
+package speech_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vito-ai/go-sdk/speech"
+	"github.com/vito-ai/go-sdk/speech/speechtest"
+)
+
+// reverseCompletion wraps the fake server's handler so that the job submitted with keyword
+// "req<i>" only completes after every job "req<j>" with j > i has completed.
+type reverseCompletion struct {
+	next http.Handler
+	n    int
+
+	mu    sync.Mutex
+	done  map[int]bool
+	order []int
+}
+
+func (rc *reverseCompletion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rc.next.ServeHTTP(w, r)
+		return
+	}
+	rec := httptest.NewRecorder()
+	rc.next.ServeHTTP(rec, r)
+	resp := &speech.RecognizeResponse{}
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), resp) != nil || resp.Status != speech.StatusCompleted {
+		copyResponse(w, rec)
+		return
+	}
+
+	i, _ := strconv.Atoi(resp.Config.Keywords[0][len("req"):])
+	rc.mu.Lock()
+	ready := true
+	for j := i + 1; j < rc.n; j++ {
+		ready = ready && rc.done[j]
+	}
+	if ready && !rc.done[i] {
+		rc.done[i] = true
+		rc.order = append(rc.order, i)
+	}
+	rc.mu.Unlock()
+
+	if !ready {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&speech.RecognizeResponse{Id: resp.Id, Status: speech.StatusTranscribing})
+		return
+	}
+	copyResponse(w, rec)
+}
+
+func copyResponse(w http.ResponseWriter, rec *httptest.ResponseRecorder) {
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	w.Write(rec.Body.Bytes())
+}
+
+func TestRecognizeBatchKeepsInputOrder(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	const n = 5
+	rc := &reverseCompletion{next: srv.Config.Handler, n: n, done: make(map[int]bool)}
+	srv.Config.Handler = rc
+
+	// every job must be in flight for the later ones to complete first
+	opt := srv.ClientOption()
+	opt.BatchConcurrency = n
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeAudio(t)
+
+	reqs := make([]*speech.RecognizeRequest, n)
+	for i := range reqs {
+		reqs[i] = &speech.RecognizeRequest{
+			Config:      speech.RecognitionConfig{Keywords: []string{"req" + strconv.Itoa(i)}},
+			AudioSource: speech.RecognitionAudio{FilePath: path},
+		}
+	}
+	results, errs := client.RecognizeBatch(context.Background(), reqs)
+
+	for i := range reqs {
+		if errs[i] != nil {
+			t.Fatalf("errs[%d] = %v", i, errs[i])
+		}
+		if got, want := results[i].Config.Keywords[0], "req"+strconv.Itoa(i); got != want {
+			t.Errorf("results[%d] belongs to %s, want %s", i, got, want)
+		}
+	}
+	for k, i := range rc.order {
+		if i != n-1-k {
+			t.Fatalf("completion order = %v, want reversed input order", rc.order)
+		}
+	}
+}
+
+// activeJobs wraps the fake server's handler to track the largest number of jobs submitted and not
+// yet completed at once.
+type activeJobs struct {
+	next http.Handler
+
+	mu           sync.Mutex
+	active, peak int
+	completed    map[speech.ResultId]bool
+}
+
+func (a *activeJobs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := httptest.NewRecorder()
+	a.next.ServeHTTP(rec, r)
+	resp := &speech.RecognizeResponse{}
+	if rec.Code == http.StatusOK && r.URL.Path != speechtest.TokenPath && json.Unmarshal(rec.Body.Bytes(), resp) == nil {
+		a.mu.Lock()
+		switch {
+		case r.Method == http.MethodPost:
+			a.active++
+			a.peak = max(a.peak, a.active)
+		case resp.Status == speech.StatusCompleted && !a.completed[resp.Id]:
+			a.completed[resp.Id] = true
+			a.active--
+		}
+		a.mu.Unlock()
+	}
+	copyResponse(w, rec)
+}
+
+func TestRecognizeBatchConcurrency(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	srv.Delay = 30 * time.Millisecond
+	a := &activeJobs{next: srv.Config.Handler, completed: make(map[speech.ResultId]bool)}
+	srv.Config.Handler = a
+
+	opt := srv.ClientOption()
+	opt.BatchConcurrency = 2
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := make([]*speech.RecognizeRequest, 6)
+	for i := range reqs {
+		reqs[i] = &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)}}
+	}
+	_, errs := client.RecognizeBatch(context.Background(), reqs)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("errs[%d] = %v", i, err)
+		}
+	}
+	if a.peak != 2 {
+		t.Fatalf("%d jobs ran at once, want 2", a.peak)
+	}
+}
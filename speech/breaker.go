@@ -0,0 +1,135 @@
+package speech
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vito-ai/go-sdk/auth/option"
+)
+
+// ErrCircuitOpen is returned instead of sending a request while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker is a RoundTripper failing requests fast after consecutive failures of base.
+type circuitBreaker struct {
+	cfg  option.CircuitBreakerConfig
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	state    option.CircuitState
+	failures int
+	openedAt time.Time
+	// whether the single half-open request is in flight
+	probing bool
+}
+
+func newCircuitBreaker(base http.RoundTripper, cfg option.CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg.WithDefaults(), base: base}
+}
+
+func (cb *circuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := cb.allow(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := cb.base.RoundTrip(req)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// cancelled by the caller, which says nothing about the server
+		cb.record(outcomeUnknown)
+	case err != nil, resp.StatusCode >= http.StatusInternalServerError:
+		cb.record(outcomeFailure)
+	default:
+		cb.record(outcomeSuccess)
+	}
+	return resp, err
+}
+
+// State returns the current state of the breaker.
+func (cb *circuitBreaker) State() option.CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// allow reports whether a request may be sent, moving an open circuit to half-open after the cooldown.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	from := cb.state
+	switch cb.state {
+	case option.CircuitOpen:
+		if time.Since(cb.openedAt) < cb.cfg.Cooldown {
+			cb.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.state = option.CircuitHalfOpen
+		cb.probing = true
+	case option.CircuitHalfOpen:
+		if cb.probing {
+			cb.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+	}
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+	return nil
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeUnknown
+)
+
+// record updates the breaker with the outcome of a request.
+func (cb *circuitBreaker) record(o outcome) {
+	cb.mu.Lock()
+	from := cb.state
+	switch cb.state {
+	case option.CircuitClosed:
+		switch o {
+		case outcomeSuccess:
+			cb.failures = 0
+		case outcomeFailure:
+			cb.failures++
+			if cb.failures >= cb.cfg.Threshold {
+				cb.open()
+			}
+		}
+	case option.CircuitHalfOpen:
+		cb.probing = false
+		switch o {
+		case outcomeSuccess:
+			cb.state = option.CircuitClosed
+			cb.failures = 0
+		case outcomeFailure:
+			cb.open()
+		}
+	}
+	// requests completing while the circuit is open were sent before it opened and are ignored
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+}
+
+func (cb *circuitBreaker) open() {
+	cb.state = option.CircuitOpen
+	cb.openedAt = time.Now()
+	cb.failures = 0
+}
+
+func (cb *circuitBreaker) notify(from, to option.CircuitState) {
+	if from != to && cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(from, to)
+	}
+}
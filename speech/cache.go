@@ -0,0 +1,89 @@
+package speech
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CacheStats reports the activity of the completed result cache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// number of results currently cached
+	Size int
+}
+
+// resultCache is an LRU cache of the raw bodies of completed results.
+// Raw bodies are cached rather than parsed responses so that callers never share a mutable response.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[ResultId]*list.Element
+	stats   CacheStats
+}
+
+type cacheEntry struct {
+	id  ResultId
+	raw []byte
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[ResultId]*list.Element),
+	}
+}
+
+func (rc *resultCache) get(id ResultId) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[id]
+	if !ok {
+		rc.stats.Misses++
+		return nil, false
+	}
+	rc.stats.Hits++
+	rc.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).raw, true
+}
+
+func (rc *resultCache) add(id ResultId, raw []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if elem, ok := rc.entries[id]; ok {
+		elem.Value.(*cacheEntry).raw = raw
+		rc.order.MoveToFront(elem)
+		return
+	}
+	rc.entries[id] = rc.order.PushFront(&cacheEntry{id: id, raw: raw})
+	if rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).id)
+		rc.stats.Evictions++
+	}
+}
+
+// remove drops the result of id, e.g. after the job was deleted from the server.
+func (rc *resultCache) remove(id ResultId) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if elem, ok := rc.entries[id]; ok {
+		rc.order.Remove(elem)
+		delete(rc.entries, id)
+	}
+}
+
+func (rc *resultCache) snapshot() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	stats := rc.stats
+	stats.Size = rc.order.Len()
+	return stats
+}
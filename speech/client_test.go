@@ -0,0 +1,339 @@
+package speech_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vito-ai/go-sdk/speech"
+	"github.com/vito-ai/go-sdk/speech/speechtest"
+)
+
+// writeAudio writes a placeholder audio file; the fake server does not decode audio.
+func writeAudio(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGetStatusResponseTooLarge(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	srv.Result = &speech.Results{Utterances: []*speech.Utterance{{Msg: strings.Repeat("a", 1024)}}}
+	opt := srv.ClientOption()
+	opt.MaxResponseBytes = 512
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := client.RecognizeAsync(context.Background(), &speech.RecognizeRequest{
+		AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetStatus(context.Background(), id); !errors.Is(err, speech.ErrResponseTooLarge) {
+		t.Fatalf("GetStatus() error = %v, want %v", err, speech.ErrResponseTooLarge)
+	}
+}
+
+func TestPreCancelledContext(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	var requests atomic.Int32
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != speechtest.TokenPath {
+			requests.Add(1)
+		}
+		return false
+	}
+	client, err := speech.NewRestClient(srv.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)}}
+
+	if _, err := client.Recognize(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("Recognize() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := client.RecognizeAsync(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RecognizeAsync() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := client.ReceiveResult(ctx, "job"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReceiveResult() error = %v, want %v", err, context.Canceled)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("%d requests were sent with a cancelled context", n)
+	}
+}
+
+func TestRetryAfterIsCapped(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	var hinted atomic.Bool
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodGet || hinted.Swap(true) {
+			return false
+		}
+		w.Header().Set("Retry-After", "86400")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":%q,"status":"transcribing"}`, path.Base(r.URL.Path))
+		return true
+	}
+	opt := srv.ClientOption()
+	opt.Polling.MaxRetryAfter = 20 * time.Millisecond
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.Recognize(ctx, &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)}})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v, want the Retry-After hint capped at MaxRetryAfter", err)
+	}
+}
+
+// pcmWav returns a 16kHz mono 16-bit PCM WAV file holding dataSize bytes of silence.
+func pcmWav(dataSize int) []byte {
+	wav := make([]byte, 44+dataSize)
+	copy(wav[0:], "RIFF")
+	binary.LittleEndian.PutUint32(wav[4:], uint32(36+dataSize))
+	copy(wav[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(wav[16:], 16)
+	binary.LittleEndian.PutUint16(wav[20:], 1)
+	binary.LittleEndian.PutUint16(wav[22:], 1)
+	binary.LittleEndian.PutUint32(wav[24:], 16000)
+	binary.LittleEndian.PutUint32(wav[28:], 32000)
+	binary.LittleEndian.PutUint16(wav[32:], 2)
+	binary.LittleEndian.PutUint16(wav[34:], 16)
+	copy(wav[36:], "data")
+	binary.LittleEndian.PutUint32(wav[40:], uint32(dataSize))
+	return wav
+}
+
+func TestRecognizeLargeTimings(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	client, err := speech.NewRestClient(srv.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one second of audio, split into four parts
+	wav := pcmWav(32000)
+
+	timings := &speech.RequestTimings{Polls: 99}
+	param := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{Content: wav}, Timings: timings}
+	if _, err := client.RecognizeLarge(context.Background(), param, 250*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if timings.Polls < 4 || timings.Upload <= 0 || timings.Total <= 0 {
+		t.Fatalf("timings = %+v, want at least one poll per part", timings)
+	}
+}
+
+func TestDebugRedactsAccessToken(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	var logs bytes.Buffer
+	opt := srv.ClientOption()
+	opt.Debug = true
+	opt.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	param := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)}}
+	if _, err := client.Recognize(context.Background(), param); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), speechtest.TokenPath) {
+		t.Fatalf("token request not dumped:\n%s", logs.String())
+	}
+	for _, secret := range []string{"speechtest-token", opt.ClientSecret} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("dump contains %q:\n%s", secret, logs.String())
+		}
+	}
+}
+
+func TestRecognizeDownloadsCompletedResultOnce(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	var gets atomic.Int32
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+		}
+		return false
+	}
+	client, err := speech.NewRestClient(srv.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	param := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)}}
+	if _, err := client.Recognize(context.Background(), param); err != nil {
+		t.Fatal(err)
+	}
+	if n := gets.Load(); n != 1 {
+		t.Fatalf("%d result requests for a job completed at the first poll, want 1", n)
+	}
+}
+
+func TestConcurrentUseAndClose(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	srv.Delay = 20 * time.Millisecond
+	client, err := speech.NewRestClient(srv.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeAudio(t)
+	ctx := context.Background()
+	closing := make(chan struct{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i == 16 {
+				close(closing)
+			}
+			param := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: path}}
+			if i%2 == 0 {
+				_, err := client.Recognize(ctx, param)
+				errs <- err
+				return
+			}
+			id, err := client.RecognizeAsync(ctx, param)
+			if err != nil {
+				errs <- err
+				return
+			}
+			_, err = client.ReceiveResult(ctx, id)
+			if errors.Is(err, speech.ErrNotFinish) {
+				err = nil
+			}
+			errs <- err
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		<-closing
+		client.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		<-closing
+		if err := client.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil && !errors.Is(err, speech.ErrClientClosed) {
+			t.Errorf("call error = %v, want nil or %v", err, speech.ErrClientClosed)
+		}
+	}
+
+	param := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: path}}
+	if _, err := client.Recognize(ctx, param); !errors.Is(err, speech.ErrClientClosed) {
+		t.Fatalf("Recognize() after Close error = %v, want %v", err, speech.ErrClientClosed)
+	}
+	if _, err := client.ReceiveResult(ctx, "job"); !errors.Is(err, speech.ErrClientClosed) {
+		t.Fatalf("ReceiveResult() after Close error = %v, want %v", err, speech.ErrClientClosed)
+	}
+}
+
+func TestJobsPolledElsewhereStayTrackedUntilForgotten(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	opt := srv.ClientOption()
+	opt.DedupeSubmissions = true
+	submitter, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poller, err := speech.NewRestClient(srv.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	param := &speech.RecognizeRequest{AudioSource: speech.RecognitionAudio{FilePath: writeAudio(t)}}
+
+	id, err := submitter.RecognizeAsync(ctx, param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := poller.ReceiveResult(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	// the submitter never sees the job finish
+	if got := submitter.InFlight(); !slices.Equal(got, []speech.ResultId{id}) {
+		t.Fatalf("InFlight() = %v, want [%s]", got, id)
+	}
+	if again, err := submitter.RecognizeAsync(ctx, param); err != nil || again != id {
+		t.Fatalf("RecognizeAsync() = %s, %v, want the deduplicated %s", again, err, id)
+	}
+
+	submitter.Forget(id)
+	if got := submitter.InFlight(); len(got) != 0 {
+		t.Fatalf("InFlight() after Forget = %v, want none", got)
+	}
+	if again, err := submitter.RecognizeAsync(ctx, param); err != nil || again == id {
+		t.Fatalf("RecognizeAsync() after Forget = %s, %v, want a new job", again, err)
+	}
+}
+
+func TestRecognizeLargeTimeoutBoundsTheWholeCall(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	srv.Delay = 40 * time.Millisecond
+	opt := srv.ClientOption()
+	opt.BatchConcurrency = 1
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio := speech.RecognitionAudio{Content: pcmWav(32000)}
+
+	// every part finishes within the timeout, but the four of them one after another do not
+	param := &speech.RecognizeRequest{AudioSource: audio, Timeout: 100 * time.Millisecond}
+	if _, err := client.RecognizeLarge(context.Background(), param, 250*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RecognizeLarge() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	param = &speech.RecognizeRequest{Config: speech.RecognitionConfig{Language: "ko"}, AudioSource: audio}
+	res, err := client.RecognizeLarge(context.Background(), param, 250*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequestConfig == nil || res.RequestConfig.Language != "ko" {
+		t.Fatalf("RequestConfig = %+v, want the submitted config", res.RequestConfig)
+	}
+}
@@ -0,0 +1,146 @@
+package speech
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader is the header used to send the request id stored with WithRequestID.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+type headersKey struct{}
+
+type pollLoggingKey struct{}
+
+type pollCallbackKey struct{}
+
+type audioDurationKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id.
+// Clients send it in the RequestIDHeader header (or gRPC metadata) of every request made with the context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id stored in ctx by WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithHeaders returns a copy of ctx carrying extra headers sent with every request made with the context.
+// Headers already stored in ctx are kept; values for the same key are appended.
+//
+// Headers from the context are applied first, so headers the SDK sets itself
+// (Authorization, Content-Type, ...) and the request id from WithRequestID take precedence.
+func WithHeaders(ctx context.Context, h http.Header) context.Context {
+	merged := http.Header{}
+	if prev, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		for k, vs := range prev {
+			merged[k] = append(merged[k], vs...)
+		}
+	}
+	for k, vs := range h {
+		merged[http.CanonicalHeaderKey(k)] = append(merged[http.CanonicalHeaderKey(k)], vs...)
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// WithPollLogging returns a copy of ctx enabling poll logging for the calls made with it, as
+// ClientOption.LogPolls does for every call. Each poll attempt is logged to the client's Logger
+// at slog.LevelInfo with the message "rtzr poll" and the fields:
+//
+//   - result_id: the id of the polled job
+//   - attempt: the number of the attempt, starting at 1
+//   - elapsed: the time since polling started
+//   - status: the status observed, empty when the attempt failed
+//   - error: the reason of a failed attempt, only present then
+func WithPollLogging(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pollLoggingKey{}, true)
+}
+
+func pollLoggingFromContext(ctx context.Context) bool {
+	enabled, _ := ctx.Value(pollLoggingKey{}).(bool)
+	return enabled
+}
+
+// PollProgress describes a poll attempt, reported to the callback of WithPollCallback.
+type PollProgress struct {
+	ResultId ResultId
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+	// Elapsed is the time since polling started.
+	Elapsed time.Duration
+	// Status is the status observed, empty when the attempt failed.
+	Status Status
+	// AudioDuration is the duration of the submitted audio, zero when unknown.
+	AudioDuration time.Duration
+	// ETA estimates the remaining transcription time: zero once completed, -1 when AudioDuration is unknown.
+	ETA time.Duration
+}
+
+// WithPollCallback returns a copy of ctx which makes the calls made with it report every poll
+// attempt to fn, from the polling goroutine.
+//
+// The ETA is a rough heuristic assuming the job is transcribed at about real time: it is the audio
+// duration minus the time spent polling so far, and zero once that is exceeded. The audio duration
+// is only known for WAV audio submitted by the same call (Recognize, RecognizeToWriter,
+// RecognizeStream, ...); waiting for an existing job by id does not know it.
+func WithPollCallback(ctx context.Context, fn func(PollProgress)) context.Context {
+	return context.WithValue(ctx, pollCallbackKey{}, fn)
+}
+
+func pollCallbackFromContext(ctx context.Context) func(PollProgress) {
+	fn, _ := ctx.Value(pollCallbackKey{}).(func(PollProgress))
+	return fn
+}
+
+// withAudioDuration returns a copy of ctx carrying the duration of audio for the poll callback,
+// when ctx has one and the duration is known.
+func withAudioDuration(ctx context.Context, audio *RecognitionAudio) context.Context {
+	if pollCallbackFromContext(ctx) == nil {
+		return ctx
+	}
+	d, ok := audio.duration(ctx)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, audioDurationKey{}, d)
+}
+
+func audioDurationFromContext(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(audioDurationKey{}).(time.Duration)
+	return d
+}
+
+// applyContextHeaders sets the headers carried by ctx on req.
+func applyContextHeaders(ctx context.Context, req *http.Request) {
+	if h, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		for k, vs := range h {
+			req.Header[k] = append([]string(nil), vs...)
+		}
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		req.Header.Set(RequestIDHeader, id)
+	}
+}
+
+// contextMetadata returns the headers carried by ctx as gRPC metadata.
+func contextMetadata(ctx context.Context) metadata.MD {
+	md := metadata.MD{}
+	if h, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		for k, vs := range h {
+			md.Append(strings.ToLower(k), vs...)
+		}
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		md.Set(strings.ToLower(RequestIDHeader), id)
+	}
+	return md
+}
@@ -0,0 +1,137 @@
+package speech
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+)
+
+// dumpTransport logs every request and response passing through it at slog.LevelInfo.
+// Only the first limit bytes of each body are logged. The Authorization header and the bodies of
+// form requests and their responses, which carry the credentials and the access token, are redacted.
+type dumpTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+	limit  int
+}
+
+func newDumpTransport(base http.RoundTripper, logger *slog.Logger, limit int) http.RoundTripper {
+	return &dumpTransport{base: base, logger: logger, limit: limit}
+}
+
+func (t *dumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	redacted := req.Clone(ctx)
+	if redacted.Header.Get("Authorization") != "" {
+		redacted.Header.Set("Authorization", "REDACTED")
+	}
+	reqDump, err := httputil.DumpRequestOut(redacted, false)
+	if err != nil {
+		return nil, err
+	}
+
+	form := isFormBody(req.Header)
+	var reqBody *prefixReadCloser
+	if req.Body != nil && req.Body != http.NoBody && !form {
+		reqBody = &prefixReadCloser{rc: req.Body, limit: t.limit}
+		r2 := new(http.Request)
+		*r2 = *req
+		r2.Body = reqBody
+		req = r2
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	t.logger.InfoContext(ctx, "rtzr http request", "dump", string(reqDump)+dumpBody(reqBody, req.Header))
+	if err != nil {
+		t.logger.InfoContext(ctx, "rtzr http request failed", "error", err)
+		return nil, err
+	}
+
+	respDump, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return nil, err
+	}
+	if form {
+		t.logger.InfoContext(ctx, "rtzr http response", "dump", string(respDump)+"[body redacted]")
+		return resp, nil
+	}
+	prefix := make([]byte, t.limit)
+	n, readErr := io.ReadFull(resp.Body, prefix)
+	prefix = prefix[:n]
+	if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+		readErr = nil
+	}
+	resp.Body = &readCloser{io.MultiReader(bytes.NewReader(prefix), errReader{readErr}, resp.Body), resp.Body}
+	t.logger.InfoContext(ctx, "rtzr http response", "dump", string(respDump)+string(prefix))
+	return resp, nil
+}
+
+func isFormBody(h http.Header) bool {
+	return strings.HasPrefix(h.Get("Content-Type"), "application/x-www-form-urlencoded")
+}
+
+func dumpBody(body *prefixReadCloser, h http.Header) string {
+	if isFormBody(h) {
+		return "[form body redacted]"
+	}
+	if body == nil {
+		return ""
+	}
+	return body.String()
+}
+
+// prefixReadCloser remembers the first limit bytes read through it.
+type prefixReadCloser struct {
+	rc    io.ReadCloser
+	limit int
+
+	mu     sync.Mutex
+	prefix []byte
+	total  int64
+}
+
+func (p *prefixReadCloser) Read(b []byte) (int, error) {
+	n, err := p.rc.Read(b)
+	p.mu.Lock()
+	if room := p.limit - len(p.prefix); room > 0 {
+		p.prefix = append(p.prefix, b[:min(n, room)]...)
+	}
+	p.total += int64(n)
+	p.mu.Unlock()
+	return n, err
+}
+
+func (p *prefixReadCloser) Close() error {
+	return p.rc.Close()
+}
+
+func (p *prefixReadCloser) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total > int64(len(p.prefix)) {
+		return string(p.prefix) + "...[truncated]"
+	}
+	return string(p.prefix)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader returns err once the preceding readers of a MultiReader are drained.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
@@ -0,0 +1,85 @@
+package speech
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+)
+
+// submission is a deduplicated submission, in progress until done is closed.
+type submission struct {
+	done chan struct{}
+	// id of the job once done, empty if the submission failed
+	id ResultId
+}
+
+// submitOnce submits param unless an identical job is in flight, whose id is then returned.
+// Concurrent identical submissions wait for the first one; if it fails, the next one submits.
+func (c *restClient) submitOnce(ctx context.Context, param *RecognizeRequest) (ResultId, error) {
+	key, err := dedupeKey(ctx, param)
+	if err != nil {
+		return "", err
+	}
+	var s *submission
+	for {
+		var ok bool
+		c.mu.Lock()
+		s, ok = c.submissions[key]
+		if !ok {
+			s = &submission{done: make(chan struct{})}
+			c.submissions[key] = s
+			c.mu.Unlock()
+			break
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if s.id != "" {
+			return s.id, nil
+		}
+	}
+
+	id, err := c.submit(ctx, param)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(s.done)
+	if _, tracked := c.jobs[id]; err != nil || !tracked {
+		// failed, or already seen finished
+		if c.submissions[key] == s {
+			delete(c.submissions, key)
+		}
+		return id, err
+	}
+	s.id = id
+	c.jobs[id] = key
+	return id, nil
+}
+
+// dedupeKey returns a hash of the config, query parameters and audio of param.
+func dedupeKey(ctx context.Context, param *RecognizeRequest) (string, error) {
+	config, err := encodeConfig(param.config())
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(config)
+	h.Write([]byte{0})
+	// Encode sorts by key
+	io.WriteString(h, param.QueryParams.Encode())
+	h.Write([]byte{0})
+
+	audio, err := param.AudioSource.open(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer audio.Close()
+	if _, err := io.Copy(h, audio); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
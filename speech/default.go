@@ -0,0 +1,43 @@
+package speech
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	defaultClientOnce sync.Once
+	defaultClient     *restClient
+	defaultClientErr  error
+)
+
+// getDefaultClient returns the package-level client, creating it on first use.
+func getDefaultClient() (*restClient, error) {
+	defaultClientOnce.Do(func() {
+		defaultClient, defaultClientErr = NewRestClient(nil)
+	})
+	return defaultClient, defaultClientErr
+}
+
+// Recognize recognizes param with a package-level client, which is created on first use with the
+// default options and the credentials from the RTZR_CLIENT_ID and RTZR_CLIENT_SECRET environment
+// variables, then reused. It is meant for scripts and experiments: production code should create
+// its own client with NewRestClient, to control its options and lifecycle.
+// If the default client cannot be created, every call returns the same error.
+func Recognize(ctx context.Context, param *RecognizeRequest) (*RecognizeResponse, error) {
+	c, err := getDefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Recognize(ctx, param)
+}
+
+// Transcribe transcribes source with the package-level client described at Recognize.
+// See (*restClient).Transcribe for the accepted sources.
+func Transcribe(ctx context.Context, config RecognitionConfig, source any) (*RecognizeResponse, error) {
+	c, err := getDefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Transcribe(ctx, config, source)
+}
@@ -0,0 +1,84 @@
+package speech
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DiffOptions controls the comparison of DiffResponses.
+type DiffOptions struct {
+	// TimingTolerance is the largest difference, in milliseconds, between utterance start times or
+	// durations which is not reported.
+	TimingTolerance int
+	// NormalizeText compares texts after Normalize, so that whitespace differences are not reported.
+	NormalizeText bool
+	// IgnoreSpeakers skips the comparison of speaker labels.
+	IgnoreSpeakers bool
+}
+
+// Difference is a difference between two responses.
+type Difference struct {
+	// Path locates the difference, e.g. "utterances[3].msg".
+	Path string
+	A, B string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %q != %q", d.Path, d.A, d.B)
+}
+
+// DiffResponses reports the differences between a and b, for comparing a transcript with a golden one.
+//
+// It compares the status, the number of utterances and, for every utterance, its text (msg),
+// speaker (spk), start time (start_at) and duration. Volatile fields are ignored: the result id,
+// ServerRequestID, RequestConfig, EngineVersion, the server config, words, alternatives and warnings.
+// Utterances are compared by position, so an inserted utterance shows up as differences in all the
+// following ones.
+func DiffResponses(a, b *RecognizeResponse, opts DiffOptions) []Difference {
+	var diffs []Difference
+	add := func(path, va, vb string) {
+		diffs = append(diffs, Difference{Path: path, A: va, B: vb})
+	}
+
+	if a.Status != b.Status {
+		add("status", string(a.Status), string(b.Status))
+	}
+	if opts.NormalizeText {
+		a, b = a.Normalize(), b.Normalize()
+	}
+	var ua, ub []*Utterance
+	if a.Results != nil {
+		ua = a.Results.Utterances
+	}
+	if b.Results != nil {
+		ub = b.Results.Utterances
+	}
+	if len(ua) != len(ub) {
+		add("utterances.length", strconv.Itoa(len(ua)), strconv.Itoa(len(ub)))
+	}
+
+	for i := range min(len(ua), len(ub)) {
+		x, y := ua[i], ub[i]
+		path := fmt.Sprintf("utterances[%d].", i)
+		if x.Msg != y.Msg {
+			add(path+"msg", x.Msg, y.Msg)
+		}
+		if !opts.IgnoreSpeakers && x.Spk != y.Spk {
+			add(path+"spk", strconv.Itoa(x.Spk), strconv.Itoa(y.Spk))
+		}
+		if abs(x.StartAt-y.StartAt) > opts.TimingTolerance {
+			add(path+"start_at", strconv.Itoa(x.StartAt), strconv.Itoa(y.StartAt))
+		}
+		if abs(x.Duration-y.Duration) > opts.TimingTolerance {
+			add(path+"duration", strconv.Itoa(x.Duration), strconv.Itoa(y.Duration))
+		}
+	}
+	return diffs
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,141 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vito-ai/go-sdk/auth"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds ClientOption.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body is too large")
+
+// ErrCorruptResponse is returned when ClientOption.VerifyResults is set and a result body does not
+// match its Content-Length or Content-MD5 header.
+var ErrCorruptResponse = errors.New("response body is corrupt")
+
+// Causes of a ValidationError, matchable with errors.Is.
+var (
+	ErrInvalidAudioSource = errors.New("invalid audio source")
+	ErrInvalidConfig      = errors.New("invalid config")
+	ErrInvalidQueryParams = errors.New("invalid query parameters")
+)
+
+// ValidationError is returned when a request is rejected before being sent.
+// It holds every problem found, so that they can be fixed at once.
+type ValidationError struct {
+	Errs []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error { return e.Errs }
+
+// newValidationError returns a ValidationError of the non-nil errs, or nil if there are none.
+// Errors joined with errors.Join are flattened.
+func newValidationError(errs ...error) error {
+	flat := flattenErrors(nil, errs)
+	if len(flat) == 0 {
+		return nil
+	}
+	return &ValidationError{Errs: flat}
+}
+
+func flattenErrors(dst, errs []error) []error {
+	for _, err := range errs {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			dst = flattenErrors(dst, joined.Unwrap())
+		} else if err != nil {
+			dst = append(dst, err)
+		}
+	}
+	return dst
+}
+
+// ServerRequestIDHeader is the response header holding the id the server assigned to a request.
+// Quote it when contacting RTZR support.
+const ServerRequestIDHeader = "X-Request-Id"
+
+// APIError is returned when the server responds with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	Body       string
+	// RequestID is the ServerRequestIDHeader of the response, if any.
+	RequestID string
+	// Redacted leaves Body out of Error, see ClientOption.RedactErrorBodies. Body is still set.
+	Redacted bool
+}
+
+func (e *APIError) Error() string {
+	body := e.Body
+	if e.Redacted {
+		body = redactedBody
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("server error : %d (request id: %s)\n%s", e.StatusCode, e.RequestID, body)
+	}
+	return fmt.Sprintf("server error : %d\n%s", e.StatusCode, body)
+}
+
+// redactedBody replaces response bodies in error messages when ClientOption.RedactErrorBodies is set.
+const redactedBody = "(response body redacted)"
+
+// apiError returns the APIError of resp, whose body is body.
+func (c *restClient) apiError(resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RequestID:  resp.Header.Get(ServerRequestIDHeader),
+		Redacted:   c.redactErrorBodies,
+	}
+}
+
+// unexpectedResponse returns the error of a result body with an unknown status.
+func (c *restClient) unexpectedResponse(body []byte) error {
+	if c.redactErrorBodies {
+		return fmt.Errorf("server response error : %s", redactedBody)
+	}
+	return fmt.Errorf("server response error : %s", string(body))
+}
+
+// withRequestID annotates err with the server request id of resp, keeping err matchable with errors.Is.
+func withRequestID(err error, resp *http.Response) error {
+	if id := resp.Header.Get(ServerRequestIDHeader); id != "" {
+		return fmt.Errorf("%w (request id: %s)", err, id)
+	}
+	return err
+}
+
+// retryableError marks an error which may succeed when the request is repeated.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+func isRetryableError(err error) bool {
+	var re *retryableError
+	return errors.As(err, &re)
+}
+
+// isRetryable reports whether a request which got resp or err is worth repeating:
+// transport failures other than cancellation, an open circuit and rejected credentials, 5xx and
+// 429 responses.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrCircuitOpen) &&
+			!errors.Is(err, auth.ErrInvalidCredentials) && !errors.Is(err, auth.ErrInvalidTokenURL)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,116 @@
+package speech
+
+import "context"
+
+// EventType is the kind of a RecognizeEvent.
+type EventType string
+
+const (
+	// EventSubmitted is sent once the audio is uploaded; ResultId is set from here on.
+	EventSubmitted EventType = "submitted"
+	// EventTranscribing is sent when the job is first seen in progress.
+	EventTranscribing EventType = "transcribing"
+	// EventPartial is sent when utterances were added to an unfinished result; Utterances holds the new ones.
+	EventPartial EventType = "partial"
+	// EventCompleted is the last event of a successful job; Response holds the full result.
+	EventCompleted EventType = "completed"
+	// EventFailed is the last event of a failed job, submission or polling; Err holds the cause.
+	EventFailed EventType = "failed"
+)
+
+// RecognizeEvent is an event of RecognizeStream.
+type RecognizeEvent struct {
+	Type     EventType
+	ResultId ResultId
+	// new utterances of an EventPartial
+	Utterances []*Utterance
+	// full result of an EventCompleted
+	Response *RecognizeResponse
+	// cause of an EventFailed
+	Err error
+}
+
+// RecognizeStream submits param and polls its result like Recognize, reporting progress as events.
+// The channel is closed after the terminal EventCompleted or EventFailed.
+//
+// The channel holds a single event. Submission and polling wait while it is full, so a slow receiver
+// slows polling down rather than losing events. The receiver must read until the channel is closed,
+// or cancel ctx: once ctx is done, the pending event is dropped and the channel is closed without a
+// terminal event.
+func (c *restClient) RecognizeStream(ctx context.Context, param *RecognizeRequest) <-chan RecognizeEvent {
+	events := make(chan RecognizeEvent, 1)
+	if err := c.begin(); err != nil {
+		events <- RecognizeEvent{Type: EventFailed, Err: err}
+		close(events)
+		return events
+	}
+
+	go func() {
+		defer c.inflight.Done()
+		defer close(events)
+
+		ctx, cancel := param.withTimeout(ctx)
+		defer cancel()
+		ctx = withAudioDuration(ctx, &param.AudioSource)
+
+		send := func(ev RecognizeEvent) bool {
+			select {
+			case events <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		resId, err := c.recognizeAsync(ctx, param)
+		if err != nil {
+			send(RecognizeEvent{Type: EventFailed, Err: err})
+			return
+		}
+		if !send(RecognizeEvent{Type: EventSubmitted, ResultId: resId}) {
+			return
+		}
+
+		transcribing := false
+		seen := 0
+		var final *RecognizeResponse
+		err = c.poll(ctx, resId, func(ctx context.Context) (Status, bool, error) {
+			res, resByte, err := c.fetchResult(ctx, resId)
+			if err != nil {
+				return "", false, err
+			}
+			switch res.Status {
+			case StatusCompleted:
+				final = res
+				return res.Status, true, nil
+			case StatusFailed:
+				return res.Status, false, res.failure()
+			case StatusTranscribing:
+			default:
+				return res.Status, false, c.unexpectedResponse(resByte)
+			}
+
+			if !transcribing {
+				transcribing = true
+				if !send(RecognizeEvent{Type: EventTranscribing, ResultId: resId}) {
+					return res.Status, false, ctx.Err()
+				}
+			}
+			if res.Results != nil && len(res.Results.Utterances) > seen {
+				added := res.Results.Utterances[seen:]
+				seen = len(res.Results.Utterances)
+				if !send(RecognizeEvent{Type: EventPartial, ResultId: resId, Utterances: added}) {
+					return res.Status, false, ctx.Err()
+				}
+			}
+			return res.Status, false, nil
+		})
+		if err != nil {
+			send(RecognizeEvent{Type: EventFailed, ResultId: resId, Err: err})
+			return
+		}
+		final.RequestConfig = param.config().submitted()
+		send(RecognizeEvent{Type: EventCompleted, ResultId: resId, Response: final})
+	}()
+	return events
+}
@@ -0,0 +1,103 @@
+package speech
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MarshalIndent returns r as JSON indented with two spaces and with object keys sorted.
+// The output is stable across SDK versions reordering struct fields,
+// which keeps diffs small when transcripts are kept under version control.
+// Numbers are written with full precision.
+func (r *RecognizeResponse) MarshalIndent() ([]byte, error) {
+	return r.marshalIndent(-1)
+}
+
+// MarshalIndentRounded is like MarshalIndent, but rounds fractional numbers
+// (such as confidences) to decimals digits after the decimal point.
+// Integers, including all timings which are in milliseconds, are left untouched.
+func (r *RecognizeResponse) MarshalIndentRounded(decimals int) ([]byte, error) {
+	return r.marshalIndent(decimals)
+}
+
+func (r *RecognizeResponse) marshalIndent(decimals int) ([]byte, error) {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// encoding/json writes map keys in sorted order
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	if decimals >= 0 {
+		tree = roundNumbers(tree, decimals)
+	}
+	return json.MarshalIndent(tree, "", "  ")
+}
+
+// roundNumbers rounds every fractional json.Number in tree to decimals digits.
+func roundNumbers(tree any, decimals int) any {
+	switch v := tree.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = roundNumbers(child, decimals)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = roundNumbers(child, decimals)
+		}
+	case json.Number:
+		if !strings.ContainsAny(string(v), ".eE") {
+			return v
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return v
+		}
+		return json.Number(strconv.FormatFloat(roundFloat(f, decimals), 'f', -1, 64))
+	}
+	return tree
+}
+
+func roundFloat(f float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(f*scale) / scale
+}
+
+// WriteCSV writes r to w as CSV, with a header row and one row per utterance.
+// The columns are id, start and end (in milliseconds), speaker, confidence and text;
+// confidence is empty when the server did not report one.
+func (r *RecognizeResponse) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "start", "end", "speaker", "confidence", "text"}); err != nil {
+		return err
+	}
+	for _, seg := range r.Utterances() {
+		confidence := ""
+		if seg.Confidence != 0 {
+			confidence = strconv.FormatFloat(seg.Confidence, 'f', -1, 64)
+		}
+		row := []string{
+			string(r.Id),
+			strconv.Itoa(seg.StartAt),
+			strconv.Itoa(seg.StartAt + seg.Duration),
+			strconv.Itoa(seg.Speaker),
+			confidence,
+			seg.Text,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,47 @@
+package speech
+
+import "context"
+
+// Job is a handle to a submitted transcription job.
+type Job struct {
+	// ID is the result id of the job.
+	ID ResultId
+
+	client *restClient
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// RecognizeJob submits param like RecognizeAsync and returns a handle to the job.
+// Submission is bound to ctx; the returned job's polling is controlled by Job.Wait and Job.Cancel.
+func (c *restClient) RecognizeJob(ctx context.Context, param *RecognizeRequest) (*Job, error) {
+	id, err := c.RecognizeAsync(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	jobCtx, cancel := context.WithCancel(context.Background())
+	return &Job{ID: id, client: c, ctx: jobCtx, cancel: cancel}, nil
+}
+
+// Wait polls until the job is completed or failed, ctx is done, or the job is canceled.
+// After Cancel, Wait returns context.Canceled.
+func (j *Job) Wait(ctx context.Context) (*RecognizeResponse, error) {
+	if err := j.client.begin(); err != nil {
+		return nil, err
+	}
+	defer j.client.inflight.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(j.ctx, cancel)
+	defer stop()
+
+	res, _, err := j.client.receiveResultWithPolling(ctx, j.ID)
+	return res, err
+}
+
+// Cancel stops any current and future Wait of the job.
+// It only stops polling; the job keeps running on the server.
+func (j *Job) Cancel() {
+	j.cancel()
+}
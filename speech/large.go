@@ -0,0 +1,159 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	wavFormatPCM   = 1
+	wavFormatFloat = 3
+)
+
+// RecognizeLarge recognizes audio longer than maxDuration by splitting it into parts of at most
+// maxDuration, recognizing the parts concurrently with RecognizeBatch, and stitching the results
+// with MergeResponses so that timestamps are relative to the start of the whole audio.
+// Audio no longer than maxDuration is recognized as a single job, like Recognize.
+//
+// Splitting happens at fixed intervals, not on silence, and is only supported for PCM or
+// IEEE float WAV audio given as Content, FilePath, or a Reader implementing io.ReaderAt.
+// Words spoken across a split point may be cut or duplicated, and speaker labels are
+// assigned per part, so they may not match across parts.
+//
+// param.Timings, when set, adds up the timings of all the parts, which run concurrently, so their
+// sum may exceed Total, the duration of the whole call.
+func (c *restClient) RecognizeLarge(ctx context.Context, param *RecognizeRequest, maxDuration time.Duration) (*RecognizeResponse, error) {
+	if maxDuration <= 0 {
+		return nil, errors.New("max duration must be positive")
+	}
+	if err := newValidationError(param.AudioSource.validate()); err != nil {
+		return nil, err
+	}
+	// param.Timeout bounds the whole call, not every part
+	ctx, cancel := param.withTimeout(ctx)
+	defer cancel()
+
+	src, size, closeSrc, err := param.AudioSource.readerAt()
+	if err != nil {
+		return nil, err
+	}
+	defer closeSrc()
+
+	wf, err := readWavFormat(io.NewSectionReader(src, 0, size))
+	if errors.Is(err, errNotWav) {
+		return nil, errors.New("only WAV audio can be split")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if (wf.AudioFormat != wavFormatPCM && wf.AudioFormat != wavFormatFloat) || wf.BlockAlign == 0 || wf.ByteRate == 0 {
+		return nil, fmt.Errorf("unsupported wav format %d for splitting", wf.AudioFormat)
+	}
+
+	dataSize := wf.DataSize
+	if available := size - wf.DataOffset; dataSize == 0 || dataSize > available {
+		dataSize = available
+	}
+	if dataSize <= int64(maxDuration.Seconds()*float64(wf.ByteRate)) {
+		return c.Recognize(ctx, param)
+	}
+
+	chunkSize := int64(maxDuration.Seconds() * float64(wf.ByteRate))
+	chunkSize -= chunkSize % int64(wf.BlockAlign)
+	if chunkSize <= 0 {
+		return nil, errors.New("max duration is shorter than a single sample")
+	}
+
+	if timings := param.Timings; timings != nil {
+		*timings = RequestTimings{}
+		start := time.Now()
+		defer func() { timings.Total = time.Since(start) }()
+	}
+
+	var reqs []*RecognizeRequest
+	var offsets []time.Duration
+	for off := int64(0); off < dataSize; off += chunkSize {
+		n := min(chunkSize, dataSize-off)
+		part := *param
+		part.Async = false
+		part.Timeout = 0
+		// every part records its own timings, which are added up once the parts are done
+		if param.Timings != nil {
+			part.Timings = &RequestTimings{}
+		}
+		part.AudioSource = RecognitionAudio{
+			Reader:   io.MultiReader(bytes.NewReader(wf.header(n)), io.NewSectionReader(src, wf.DataOffset+off, n)),
+			FileName: fmt.Sprintf("part-%d.wav", len(reqs)),
+		}
+		reqs = append(reqs, &part)
+		offsets = append(offsets, bytesDuration(off, int64(wf.ByteRate)))
+	}
+
+	results, errs := c.RecognizeBatch(ctx, reqs)
+	if timings := param.Timings; timings != nil {
+		for _, part := range reqs {
+			timings.Auth += part.Timings.Auth
+			timings.Upload += part.Timings.Upload
+			timings.Polls += part.Timings.Polls
+			timings.Polling += part.Timings.Polling
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	merged, err := MergeResponses(offsets, results...)
+	if err != nil {
+		return nil, err
+	}
+	merged.RequestConfig = param.config().submitted()
+	return merged, nil
+}
+
+// bytesDuration returns the duration of n bytes of audio at byteRate, without overflowing for
+// sizes beyond what time.Duration(n)*time.Second can hold.
+func bytesDuration(n, byteRate int64) time.Duration {
+	return time.Duration(n/byteRate)*time.Second + time.Duration(n%byteRate)*time.Second/time.Duration(byteRate)
+}
+
+// readerAt returns random access to the audio, its size, and a function releasing it.
+func (ra *RecognitionAudio) readerAt() (io.ReaderAt, int64, func(), error) {
+	switch {
+	case ra.FilePath != "":
+		f, err := os.Open(ra.FilePath)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, nil, err
+		}
+		return f, info.Size(), func() { f.Close() }, nil
+	case ra.Content != nil:
+		return bytes.NewReader(ra.Content), int64(len(ra.Content)), func() {}, nil
+	default:
+		rat, ok := ra.Reader.(io.ReaderAt)
+		rs, seekable := ra.Reader.(io.Seeker)
+		if !ok || !seekable {
+			return nil, 0, nil, errors.New("splitting requires Content, FilePath or a Reader implementing io.ReaderAt and io.Seeker")
+		}
+		pos, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		size, err := rs.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		// the audio may still be uploaded as is from its current position
+		if _, err := rs.Seek(pos, io.SeekStart); err != nil {
+			return nil, 0, nil, err
+		}
+		return rat, size, func() {}, nil
+	}
+}
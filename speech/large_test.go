@@ -0,0 +1,16 @@
+package speech
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBytesDuration(t *testing.T) {
+	// 20GB of 16kHz 16-bit mono audio overflows time.Duration(n) * time.Second
+	const byteRate = 32000
+	n := int64(20 << 30)
+	want := time.Duration(float64(n) / byteRate * float64(time.Second))
+	if got := bytesDuration(n, byteRate); got < want-time.Microsecond || got > want+time.Microsecond {
+		t.Fatalf("bytesDuration(%d, %d) = %v, want %v", n, byteRate, got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package speech
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// MergeResponses concatenates the utterances of resps into a single response.
+// Timestamps of each response (utterances and words) are shifted by the offset at the same index,
+// so offsets[i] should be the position of the i-th audio segment within the whole audio.
+//
+// All responses must be completed, and responses which report their Config must report the same one.
+// The merged response has no Id, since it does not correspond to a single job.
+func MergeResponses(offsets []time.Duration, resps ...*RecognizeResponse) (*RecognizeResponse, error) {
+	if len(offsets) != len(resps) {
+		return nil, fmt.Errorf("got %d offsets for %d responses", len(offsets), len(resps))
+	}
+	if len(resps) == 0 {
+		return nil, errors.New("no responses to merge")
+	}
+
+	merged := &RecognizeResponse{
+		Status:  StatusCompleted,
+		Results: &Results{Verified: true},
+	}
+	for i, resp := range resps {
+		if resp == nil {
+			return nil, fmt.Errorf("response %d is nil", i)
+		}
+		if resp.Status != StatusCompleted {
+			return nil, fmt.Errorf("response %d is not completed: %s", i, resp.Status)
+		}
+		if resp.Config != nil {
+			if merged.Config == nil {
+				merged.Config = resp.Config
+			} else if !reflect.DeepEqual(merged.Config, resp.Config) {
+				return nil, fmt.Errorf("response %d was transcribed with a different config", i)
+			}
+		}
+		if resp.Results == nil {
+			continue
+		}
+		merged.Results.Verified = merged.Results.Verified && resp.Results.Verified
+
+		shift := int(offsets[i].Milliseconds())
+		for _, u := range resp.Results.Utterances {
+			merged.Results.Utterances = append(merged.Results.Utterances, u.shifted(shift))
+		}
+	}
+	return merged, nil
+}
+
+// shifted returns a copy of u with its timestamps moved by ms milliseconds.
+func (u *Utterance) shifted(ms int) *Utterance {
+	cp := *u
+	cp.StartAt += ms
+	if u.Words != nil {
+		cp.Words = make([]*TimeStampWord, len(u.Words))
+		for i, w := range u.Words {
+			wc := *w
+			wc.StartAt += ms
+			cp.Words[i] = &wc
+		}
+	}
+	return &cp
+}
@@ -0,0 +1,84 @@
+package speech
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeOptions selects the clean-ups applied by NormalizeWith.
+type NormalizeOptions struct {
+	// SmartQuotes replaces typographic quotes (“ ” ‘ ’) with their ASCII counterparts.
+	SmartQuotes bool
+	// StripControl removes byte order marks (U+FEFF) and control characters other than
+	// tabs and newlines, which break tools such as CSV importers. Tabs and newlines are then
+	// collapsed like any other whitespace, unless KeepWhitespace is set.
+	StripControl bool
+	// KeepWhitespace leaves whitespace as is instead of trimming and collapsing it, so that the
+	// other clean-ups can be applied on their own.
+	KeepWhitespace bool
+}
+
+var smartQuoteReplacer = strings.NewReplacer("“", `"`, "”", `"`, "‘", "'", "’", "'")
+
+// Normalize returns a copy of r whose utterance, word and alternative texts have
+// leading and trailing whitespace trimmed and inner runs of whitespace collapsed to one space.
+// r is not modified.
+func (r *RecognizeResponse) Normalize() *RecognizeResponse {
+	return r.NormalizeWith(NormalizeOptions{})
+}
+
+// NormalizeWith is like Normalize, additionally applying opts.
+func (r *RecognizeResponse) NormalizeWith(opts NormalizeOptions) *RecognizeResponse {
+	cp := *r
+	if r.Results == nil {
+		return &cp
+	}
+	results := *r.Results
+	results.Utterances = make([]*Utterance, len(r.Results.Utterances))
+	for i, u := range r.Results.Utterances {
+		results.Utterances[i] = u.normalized(opts)
+	}
+	cp.Results = &results
+	return &cp
+}
+
+// normalized returns a copy of u with its texts normalized.
+func (u *Utterance) normalized(opts NormalizeOptions) *Utterance {
+	cp := *u
+	cp.Msg = normalizeText(u.Msg, opts)
+	if u.Words != nil {
+		cp.Words = make([]*TimeStampWord, len(u.Words))
+		for i, w := range u.Words {
+			wc := *w
+			wc.Text = normalizeText(w.Text, opts)
+			cp.Words[i] = &wc
+		}
+	}
+	if u.Alternatives != nil {
+		cp.Alternatives = make([]*Alternative, len(u.Alternatives))
+		for i, a := range u.Alternatives {
+			ac := *a
+			ac.Msg = normalizeText(a.Msg, opts)
+			cp.Alternatives[i] = &ac
+		}
+	}
+	return &cp
+}
+
+func normalizeText(s string, opts NormalizeOptions) string {
+	if opts.StripControl {
+		s = strings.Map(func(r rune) rune {
+			if r == '\ufeff' || (unicode.IsControl(r) && r != '\t' && r != '\n') {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if opts.SmartQuotes {
+		s = smartQuoteReplacer.Replace(s)
+	}
+	if opts.KeepWhitespace {
+		return s
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,19 @@
+package speech
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		opts NormalizeOptions
+		want string
+	}{
+		{NormalizeOptions{}, "\ufeffline1 line2\x07"},
+		{NormalizeOptions{StripControl: true}, "line1 line2"},
+		{NormalizeOptions{StripControl: true, KeepWhitespace: true}, " line1\n\tline2"},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(" \ufeffline1\n\tline2\x07", tt.opts); got != tt.want {
+			t.Errorf("normalizeText(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package speech
+
+import (
+	"time"
+
+	"github.com/vito-ai/go-sdk/auth/option"
+)
+
+// EstimatePollCount estimates how many polls a job with audioDuration of audio needs under cfg.
+// It assumes that transcription takes about as long as the audio itself (real-time processing),
+// and counts every poll up to and including the one which observes completion, starting
+// with the immediate check made right after submission.
+// Unset fields of cfg take their defaults.
+func EstimatePollCount(audioDuration time.Duration, cfg option.PollingConfig) int {
+	cfg = cfg.WithDefaults()
+
+	count := 1
+	elapsed := time.Duration(0)
+	delay := cfg.Interval
+	for elapsed < audioDuration {
+		elapsed += delay
+		count++
+		delay = cfg.Next(delay)
+	}
+	return count
+}
@@ -0,0 +1,263 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	pb "github.com/vito-ai/go-genproto/vito-openapi/stt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReconnectPolicy는 ReconnectingStream이 끊어진 스트림을 다시 연결하는 방식입니다.
+//
+// 다시 연결하면 처음 전송한 설정(StreamingConfig)을 다시 보낸 뒤, 최근에 전송한 음성 중 BufferBytes만큼을
+// 다시 전송하고 이어서 전송합니다. 연결이 끊어진 동안 서버가 처리하지 못한 음성을 다시 보내기 위한 것으로,
+// 버퍼가 클수록 끊어진 구간의 단어를 잃을 가능성은 줄어들지만 이미 인식된 음성도 다시 전송되므로
+// 이어지는 지점에서 단어가 중복될 수 있습니다. 버퍼가 작으면 중복은 줄지만 단어가 누락될 수 있습니다.
+// 새 스트림의 결과 시간은 다시 전송한 음성의 시작을 기준으로 합니다.
+type ReconnectPolicy struct {
+	// 연속으로 다시 연결을 시도하는 최대 횟수입니다. 기본값은 3입니다.
+	MaxAttempts int
+	// 첫 재연결 전의 대기 시간이며, 시도마다 두 배가 됩니다. 기본값은 500ms입니다.
+	Backoff time.Duration
+	// 다시 전송하기 위해 보관하는 최근 음성의 크기(바이트)입니다.
+	// 기본값은 16kHz 16-bit 모노 음성 3초에 해당하는 96000 바이트이며, 음수이면 음성을 다시 전송하지 않습니다.
+	BufferBytes int
+	// Reconnected는 다시 연결할 때마다 연결이 끊어진 원인과 함께 Recv를 호출한 goroutine에서 호출됩니다.
+	Reconnected func(cause error)
+}
+
+func (p ReconnectPolicy) withDefaults() ReconnectPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = 500 * time.Millisecond
+	}
+	if p.BufferBytes == 0 {
+		p.BufferBytes = 96000
+	}
+	return p
+}
+
+// ReconnectingStream은 일시적인 네트워크 오류(codes.Unavailable)로 끊어진 스트림을 policy에 따라
+// 다시 연결하는 스트림입니다. CloseSend 이후에는 다시 연결하지 않습니다.
+//
+// gRPC 스트림에서 연결이 끊어진 원인은 Recv로만 전달되므로, Send는 전송에 실패하면 Recv가 원인을 받아
+// 다시 연결할 때까지 기다립니다. 따라서 Send와 Recv를 서로 다른 goroutine에서 동시에 호출해야 합니다.
+// 각각을 여러 goroutine에서 동시에 호출하면 안 됩니다.
+type ReconnectingStream struct {
+	client *gRPCClient
+	ctx    context.Context
+	policy ReconnectPolicy
+
+	// cur, config, tail, closeSent, err를 보호합니다. 다시 연결하는 동안 대기하거나 스트림을 여는 중에는
+	// 잠그지 않으며, 보관한 음성을 다시 전송하고 새 스트림으로 교체할 때만 잠급니다.
+	mu        sync.Mutex
+	cur       *generation
+	config    *pb.DecoderRequest
+	tail      [][]byte
+	tailSize  int
+	closeSent bool
+	// 스트림을 종료시킨 에러이며, 이후의 Send와 Recv는 이 에러를 반환합니다.
+	err error
+}
+
+// generation은 다시 연결할 때마다 새로 여는 스트림입니다.
+type generation struct {
+	stream pb.OnlineDecoder_DecodeClient
+	cancel context.CancelFunc
+	// 스트림이 새 스트림으로 교체되거나 종료되면 닫힙니다.
+	done chan struct{}
+	// 스트림을 종료시킨 에러이며, 교체된 경우 nil입니다. done이 닫힌 뒤에 읽어야 합니다.
+	err error
+}
+
+// StreamingRecognizeWithReconnect는 StreamingRecognize와 같이 스트림을 열고, 끊어지면 policy에 따라 다시 연결합니다.
+func (c *gRPCClient) StreamingRecognizeWithReconnect(ctx context.Context, policy ReconnectPolicy) (*ReconnectingStream, error) {
+	s := &ReconnectingStream{client: c, ctx: ctx, policy: policy.withDefaults()}
+	g, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	s.cur = g
+	return s, nil
+}
+
+func (s *ReconnectingStream) open() (*generation, error) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	stream, err := s.client.StreamingRecognize(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &generation{stream: stream, cancel: cancel, done: make(chan struct{})}, nil
+}
+
+// Send는 req를 전송합니다. 설정은 다시 연결할 때 다시 보내기 위해 보관하고, 음성은 최근 BufferBytes만큼 보관합니다.
+func (s *ReconnectingStream) Send(req *pb.DecoderRequest) error {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return s.err
+	}
+	replayed := false
+	switch r := req.GetStreamingRequest().(type) {
+	case *pb.DecoderRequest_StreamingConfig:
+		s.config = req
+		replayed = true
+	case *pb.DecoderRequest_AudioContent:
+		replayed = s.remember(r.AudioContent)
+	}
+	g := s.cur
+	s.mu.Unlock()
+
+	for {
+		err := g.stream.Send(req)
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		// 스트림이 끊어졌습니다. Recv가 원인을 받아 처리할 때까지 기다립니다.
+		select {
+		case <-g.done:
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+		if g.err != nil {
+			return g.err
+		}
+		if replayed {
+			// 다시 연결하면서 이미 전송했습니다.
+			return nil
+		}
+		s.mu.Lock()
+		g = s.cur
+		s.mu.Unlock()
+	}
+}
+
+// remember는 audio를 다시 전송할 음성으로 보관하고, BufferBytes를 넘는 오래된 음성을 버립니다.
+// audio를 보관했는지 반환합니다.
+func (s *ReconnectingStream) remember(audio []byte) bool {
+	if s.policy.BufferBytes <= 0 {
+		return false
+	}
+	s.tail = append(s.tail, audio)
+	s.tailSize += len(audio)
+	for len(s.tail) > 1 && s.tailSize-len(s.tail[0]) >= s.policy.BufferBytes {
+		s.tailSize -= len(s.tail[0])
+		s.tail = s.tail[1:]
+	}
+	return true
+}
+
+// Recv는 응답을 받습니다. 일시적인 오류로 스트림이 끊어지면 다시 연결한 뒤 새 스트림에서 받습니다.
+func (s *ReconnectingStream) Recv() (*pb.DecoderResponse, error) {
+	for {
+		s.mu.Lock()
+		g, err := s.cur, s.err
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		res, cause := g.stream.Recv()
+		if cause == nil {
+			return res, nil
+		}
+		if err := s.reconnect(g, cause); err != nil {
+			return nil, err
+		}
+		if s.policy.Reconnected != nil {
+			s.policy.Reconnected(cause)
+		}
+	}
+}
+
+// CloseSend는 음성 전송을 종료합니다. 이후에는 다시 연결하지 않습니다.
+func (s *ReconnectingStream) CloseSend() error {
+	s.mu.Lock()
+	s.closeSent = true
+	g := s.cur
+	s.mu.Unlock()
+	return g.stream.CloseSend()
+}
+
+// reconnect는 스트림 old가 cause로 끊어졌을 때 다시 연결합니다. 다시 연결할 수 없으면 스트림을 종료하고
+// 에러를 반환하며, 다시 연결할 수 없는 오류이면 cause를 그대로 반환합니다.
+func (s *ReconnectingStream) reconnect(old *generation, cause error) error {
+	s.mu.Lock()
+	closeSent := s.closeSent
+	s.mu.Unlock()
+	if closeSent || status.Code(cause) != codes.Unavailable || s.ctx.Err() != nil {
+		return s.end(old, cause)
+	}
+	old.cancel()
+
+	backoff := s.policy.Backoff
+	errs := []error{cause}
+	for attempt := 0; attempt < s.policy.MaxAttempts; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return s.end(old, s.ctx.Err())
+		}
+		backoff *= 2
+
+		g, err := s.open()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// 다시 전송한 뒤 교체할 때까지 잠가서, 그 사이에 Send가 보관한 음성이 누락되지 않도록 합니다.
+		s.mu.Lock()
+		if err := s.replay(g.stream); err != nil {
+			s.mu.Unlock()
+			g.cancel()
+			errs = append(errs, err)
+			continue
+		}
+		s.cur = g
+		closeSent := s.closeSent
+		s.mu.Unlock()
+		close(old.done)
+		if closeSent {
+			// 다시 연결하는 동안 CloseSend가 이전 스트림에 호출되었습니다.
+			g.stream.CloseSend()
+		}
+		return nil
+	}
+	return s.end(old, errors.Join(errs...))
+}
+
+// end는 스트림 old를 err로 종료합니다. 이후의 Send와 Recv는 err를 반환합니다.
+func (s *ReconnectingStream) end(old *generation, err error) error {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	old.err = err
+	old.cancel()
+	close(old.done)
+	return err
+}
+
+// replay는 보관한 설정과 음성을 stream으로 다시 전송합니다. s.mu를 잠근 상태에서 호출해야 합니다.
+func (s *ReconnectingStream) replay(stream pb.OnlineDecoder_DecodeClient) error {
+	if s.config != nil {
+		if err := stream.Send(s.config); err != nil {
+			return err
+		}
+	}
+	for _, audio := range s.tail {
+		if err := stream.Send(&pb.DecoderRequest{
+			StreamingRequest: &pb.DecoderRequest_AudioContent{AudioContent: audio},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
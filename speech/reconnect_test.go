@@ -0,0 +1,177 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/vito-ai/go-genproto/vito-openapi/stt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vito-ai/go-sdk/auth"
+)
+
+type staticTokenProvider struct{}
+
+func (staticTokenProvider) Token(context.Context) (*auth.ReturnZeroToken, error) {
+	return &auth.ReturnZeroToken{AccessToken: "token", ExpireAt: time.Now().Add(time.Hour).Unix()}, nil
+}
+
+// scriptedStream returns results, then recvErr. With a nil recvErr, Recv blocks until the stream's
+// context is done.
+type scriptedStream struct {
+	grpc.ClientStream
+	ctx     context.Context
+	results []*pb.DecoderResponse
+	recvErr error
+
+	mu        sync.Mutex
+	sent      []*pb.DecoderRequest
+	closeSent bool
+}
+
+func (s *scriptedStream) Send(req *pb.DecoderRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, req)
+	return nil
+}
+
+func (s *scriptedStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeSent = true
+	return nil
+}
+
+func (s *scriptedStream) Recv() (*pb.DecoderResponse, error) {
+	if len(s.results) > 0 {
+		res := s.results[0]
+		s.results = s.results[1:]
+		return res, nil
+	}
+	if s.recvErr != nil {
+		return nil, s.recvErr
+	}
+	<-s.ctx.Done()
+	return nil, status.FromContextError(s.ctx.Err()).Err()
+}
+
+// scriptedDecoder opens the streams in order.
+type scriptedDecoder struct {
+	mu      sync.Mutex
+	streams []*scriptedStream
+	opened  int
+}
+
+func (d *scriptedDecoder) Decode(ctx context.Context, _ ...grpc.CallOption) (pb.OnlineDecoder_DecodeClient, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.opened == len(d.streams) {
+		return nil, status.Error(codes.Unavailable, "no more streams")
+	}
+	s := d.streams[d.opened]
+	d.opened++
+	s.ctx = ctx
+	return s, nil
+}
+
+func newScriptedClient(streams ...*scriptedStream) *gRPCClient {
+	return &gRPCClient{client: &scriptedDecoder{streams: streams}, tp: staticTokenProvider{}}
+}
+
+func audioRequest(audio string) *pb.DecoderRequest {
+	return &pb.DecoderRequest{StreamingRequest: &pb.DecoderRequest_AudioContent{AudioContent: []byte(audio)}}
+}
+
+func TestReconnectingStreamReplays(t *testing.T) {
+	first := &scriptedStream{recvErr: status.Error(codes.Unavailable, "connection reset")}
+	second := &scriptedStream{results: []*pb.DecoderResponse{{}}, recvErr: io.EOF}
+	var causes []error
+	s, err := newScriptedClient(first, second).StreamingRecognizeWithReconnect(context.Background(), ReconnectPolicy{
+		Backoff:     time.Millisecond,
+		BufferBytes: 4,
+		Reconnected: func(cause error) { causes = append(causes, cause) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &pb.DecoderRequest{StreamingRequest: &pb.DecoderRequest_StreamingConfig{}}
+	for _, req := range []*pb.DecoderRequest{config, audioRequest("ab"), audioRequest("cd"), audioRequest("ef")} {
+		if err := s.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := s.Recv(); err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if len(causes) != 1 || status.Code(causes[0]) != codes.Unavailable {
+		t.Fatalf("Reconnected causes = %v, want one Unavailable", causes)
+	}
+	// the config and the last BufferBytes of audio are sent again
+	if len(second.sent) != 3 || second.sent[0] != config ||
+		string(second.sent[1].GetAudioContent()) != "cd" || string(second.sent[2].GetAudioContent()) != "ef" {
+		t.Fatalf("replayed %v", second.sent)
+	}
+	if first.ctx.Err() == nil {
+		t.Fatal("the dropped stream was not cancelled")
+	}
+}
+
+func TestReconnectingStreamEnds(t *testing.T) {
+	stream := &scriptedStream{recvErr: io.EOF}
+	s, err := newScriptedClient(stream).StreamingRecognizeWithReconnect(context.Background(), ReconnectPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every call after the end returns the same error instead of using the ended stream
+	for range 2 {
+		if _, err := s.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("Recv() error = %v, want %v", err, io.EOF)
+		}
+	}
+	if err := s.Send(audioRequest("ab")); !errors.Is(err, io.EOF) {
+		t.Fatalf("Send() error = %v, want %v", err, io.EOF)
+	}
+	if stream.ctx.Err() == nil {
+		t.Fatal("the context of the ended stream was not cancelled")
+	}
+}
+
+func TestReconnectingStreamCloseSendDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := &scriptedStream{recvErr: status.Error(codes.Unavailable, "connection reset")}
+	s, err := newScriptedClient(first).StreamingRecognizeWithReconnect(ctx, ReconnectPolicy{Backoff: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recvErr := make(chan error, 1)
+	go func() {
+		_, err := s.Recv()
+		recvErr <- err
+	}()
+	// the dropped stream is cancelled before the backoff starts
+	<-first.ctx.Done()
+
+	closed := make(chan error, 1)
+	go func() { closed <- s.CloseSend() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CloseSend() blocked while reconnecting")
+	}
+	cancel()
+	if err := <-recvErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Recv() error = %v, want %v", err, context.Canceled)
+	}
+}
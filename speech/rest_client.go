@@ -3,13 +3,20 @@ package speech
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/vito-ai/go-sdk/auth"
@@ -18,228 +25,1389 @@ import (
 
 var ErrNotFinish = errors.New("result is not complete yet")
 var ErrFailed = errors.New("result failed")
+var ErrResultGone = errors.New("result no longer exists")
+var ErrClientClosed = errors.New("client is closed")
+var ErrInvalidEndpoint = errors.New("invalid endpoint")
 
 type restClient struct {
+	// options the client was created with, with a resolved Transport
+	opts *option.ClientOption
+
+	// circuit breaker wrapped in opts.Transport, nil when disabled
+	breaker *circuitBreaker
+
 	// endpoint to rtzr api server host
 	endpoint string
 
+	// endpoint results are fetched from, the endpoint itself by default
+	resultEndpoint string
+
+	// method of submit requests
+	submitMethod string
+
+	// optional paths overriding the endpoint path for submit and result requests
+	submitPath         string
+	resultPathTemplate string
+
 	//httpClient
 	httpClient *http.Client
+
+	// token provider of httpClient
+	tp auth.TokenProvider
+
+	// buffer size for copying audio into the multipart body
+	copyBufferSize int
+
+	// largest Content uploaded from a reused buffer instead of a pipe
+	inMemoryUploadThreshold int
+
+	// largest number of RecognizeBatch requests running at once, negative when unlimited
+	batchConcurrency int
+
+	// query parameters appended to every submit request
+	queryParams url.Values
+
+	// smallest audio size sent as a raw body, zero when disabled
+	rawUploadThreshold int64
+
+	// number of consecutive transient poll failures to tolerate
+	pollRetry int
+
+	// destination of the SDK's logs, and whether every poll attempt is logged
+	logger   *slog.Logger
+	logPolls bool
+
+	// classifies transient poll failures, nil for isRetryable
+	retryPolicy func(resp *http.Response, err error) bool
+
+	// delay before hedging a result request, zero when disabled
+	hedgeDelay time.Duration
+
+	// interval and backoff between polls
+	polling option.PollingConfig
+
+	// resubmission of failed jobs
+	failedRetries         int
+	permanentFailureCodes []string
+
+	// maximum size of a response body
+	maxResponseBytes int64
+
+	// whether result bodies are checked against their headers
+	verifyResults bool
+
+	// Accept-Language header of every request
+	acceptLanguage string
+
+	// whether response bodies are left out of error messages
+	redactErrorBodies bool
+
+	// completed results, nil when caching is disabled
+	cache *resultCache
+
+	// guards closed, jobs and submissions; in-flight operations are counted in inflight
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+
+	// jobs submitted through this client which were not seen finished yet, with their dedupe key
+	jobs map[ResultId]string
+
+	// whether identical submissions are deduplicated, and the in-flight ones by dedupe key
+	dedupe      bool
+	submissions map[string]*submission
 }
 
 // Make New Client for RESTful STT API
+//
+// The client is safe for concurrent use by multiple goroutines, and a single client
+// should be shared rather than created per request. Close (or Shutdown) may be called
+// concurrently with other methods: operations already running complete normally and
+// operations started afterwards fail with ErrClientClosed.
 func NewRestClient(cliopts *option.ClientOption) (*restClient, error) {
 	if cliopts == nil {
 		cliopts = option.DefaultClientOption()
 	}
-	httpClient, err := auth.NewAuthClient(cliopts)
+	if err := newValidationError(validateQueryParams(cliopts.QueryParams)); err != nil {
+		return nil, err
+	}
+	if tmpl := cliopts.ResultPathTemplate; tmpl != "" && !strings.Contains(tmpl, resultIdPlaceholder) {
+		return nil, fmt.Errorf("result path template %q must contain %s", tmpl, resultIdPlaceholder)
+	}
+	switch method := cliopts.GetSubmitMethod(); method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil, fmt.Errorf("submit method %q does not accept a request body; use POST, PUT or PATCH", method)
+	}
+	endpoint, err := normalizeEndpoint(cliopts.GetRestEndpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	resultEndpoint := endpoint
+	if cliopts.ResultEndpoint != "" {
+		if resultEndpoint, err = normalizeEndpoint(cliopts.ResultEndpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	// the transport is resolved once, so that clients derived with WithCredentials share it
+	resolved := *cliopts
+	resolved.Endpoint = endpoint
+	resolved.ResultEndpoint = resultEndpoint
+	resolved.Transport = cliopts.GetTransport()
+	if cliopts.Debug {
+		resolved.Transport = newDumpTransport(resolved.Transport, cliopts.GetLogger(), cliopts.GetDebugBodyLimit())
+	}
+	var breaker *circuitBreaker
+	if cliopts.CircuitBreaker != nil {
+		breaker = newCircuitBreaker(resolved.Transport, *cliopts.CircuitBreaker)
+		resolved.Transport = breaker
+	}
+	return newRestClient(&resolved, breaker)
+}
+
+// normalizeEndpoint checks that endpoint is an absolute http(s) URL and strips its trailing slashes,
+// so that result URLs built by appending "/{id}" never contain a double slash.
+func normalizeEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidEndpoint, endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w %q: scheme must be http or https", ErrInvalidEndpoint, endpoint)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w %q: host is missing", ErrInvalidEndpoint, endpoint)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w %q: query and fragment are not allowed; use QueryParams", ErrInvalidEndpoint, endpoint)
+	}
+	return strings.TrimRight(endpoint, "/"), nil
+}
+
+// newRestClient returns a client for validated cliopts with a resolved Transport, which includes breaker if any.
+func newRestClient(cliopts *option.ClientOption, breaker *circuitBreaker) (*restClient, error) {
+	tp, err := auth.NewRTZRTokenProvider(cliopts)
 	if err != nil {
 		return nil, err
 	}
 
 	c := &restClient{
-		endpoint:   cliopts.GetRestEndpoint(),
-		httpClient: httpClient,
+		opts:                    cliopts,
+		breaker:                 breaker,
+		endpoint:                cliopts.GetRestEndpoint(),
+		resultEndpoint:          cliopts.ResultEndpoint,
+		submitMethod:            cliopts.GetSubmitMethod(),
+		submitPath:              cliopts.SubmitPath,
+		resultPathTemplate:      cliopts.ResultPathTemplate,
+		httpClient:              auth.NewAuthClientWithTokenProvider(cliopts, tp),
+		tp:                      tp,
+		copyBufferSize:          cliopts.GetCopyBufferSize(),
+		inMemoryUploadThreshold: cliopts.GetInMemoryUploadThreshold(),
+		batchConcurrency:        cliopts.GetBatchConcurrency(),
+		queryParams:             cliopts.QueryParams,
+		rawUploadThreshold:      cliopts.RawUploadThreshold,
+		pollRetry:               cliopts.PollRetry,
+		logger:                  cliopts.GetLogger(),
+		logPolls:                cliopts.LogPolls,
+		retryPolicy:             cliopts.RetryPolicy,
+		hedgeDelay:              cliopts.HedgeDelay,
+		polling:                 cliopts.GetPolling(),
+		failedRetries:           cliopts.FailedRetries,
+		permanentFailureCodes:   cliopts.PermanentFailureCodes,
+		maxResponseBytes:        cliopts.GetMaxResponseBytes(),
+		verifyResults:           cliopts.VerifyResults,
+		acceptLanguage:          cliopts.GetAcceptLanguage(),
+		redactErrorBodies:       cliopts.RedactErrorBodies,
+		jobs:                    make(map[ResultId]string),
+		dedupe:                  cliopts.DedupeSubmissions,
+		submissions:             make(map[string]*submission),
+	}
+	if cliopts.ResultCacheSize > 0 {
+		c.cache = newResultCache(cliopts.ResultCacheSize)
 	}
 
 	return c, nil
 }
 
+// WithCredentials returns a client which authenticates with clientId and clientSecret instead of the
+// credentials of c, and otherwise behaves like c. It shares the connections of c, so deriving one
+// client per tenant is cheap.
+//
+// Each derived client fetches and caches its own access token, and has its own result cache and
+// lifecycle: closing c does not close the derived client. Keep the derived client for as long as the
+// tenant is active, rather than deriving one per request, to reuse its token.
+func (c *restClient) WithCredentials(clientId, clientSecret string) (*restClient, error) {
+	if clientId == "" || clientSecret == "" {
+		// never fall back to the environment for a tenant
+		return nil, fmt.Errorf("%w: client id and secret must be provided", auth.ErrInvalidCredentials)
+	}
+	opts := *c.opts
+	opts.ClientId = clientId
+	opts.ClientSecret = clientSecret
+	opts.CredentialsFile = ""
+	return newRestClient(&opts, c.breaker)
+}
+
+// CircuitState returns the state of the circuit breaker, which is always closed when
+// ClientOption.CircuitBreaker is not set.
+func (c *restClient) CircuitState() option.CircuitState {
+	if c.breaker == nil {
+		return option.CircuitClosed
+	}
+	return c.breaker.State()
+}
+
+// Close stops accepting new requests without waiting for in-flight operations.
 func (c *restClient) Close() error {
-	c.httpClient = nil
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return nil
+}
+
+// Shutdown stops accepting new requests and waits for in-flight operations to finish.
+// It returns nil if they all finished, or ctx.Err() if ctx expired first.
+// Operations started after Shutdown fail with ErrClientClosed.
+func (c *restClient) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin registers an in-flight operation, which must be finished with c.inflight.Done().
+func (c *restClient) begin() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClientClosed
+	}
+	c.inflight.Add(1)
 	return nil
 }
 
+// Warmup fetches an access token and opens a connection to the endpoint, so that the
+// first request does not pay for them. It may be called any number of times, also
+// concurrently; a valid token is not fetched again.
+func (c *restClient) Warmup(ctx context.Context) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.inflight.Done()
+
+	if _, err := c.tp.Token(ctx); err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodHead, c.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	// any status will do, the connection stays in the pool
+	io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
 func (c *restClient) Recognize(ctx context.Context, param *RecognizeRequest) (*RecognizeResponse, error) {
-	resId, err := c.RecognizeAsync(ctx, param)
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.Done()
+	return c.recognize(ctx, param)
+}
+
+func (c *restClient) recognize(ctx context.Context, param *RecognizeRequest) (*RecognizeResponse, error) {
+	resp, _, err := c.recognizeRaw(ctx, param)
+	return resp, err
+}
+
+// RecognizeRaw is like Recognize, but also returns the exact JSON body the server returned
+// for the completed result. The raw body is nil in Async mode.
+func (c *restClient) RecognizeRaw(ctx context.Context, param *RecognizeRequest) (*RecognizeResponse, []byte, error) {
+	if err := c.begin(); err != nil {
+		return nil, nil, err
+	}
+	defer c.inflight.Done()
+	return c.recognizeRaw(ctx, param)
+}
+
+func (c *restClient) recognizeRaw(ctx context.Context, param *RecognizeRequest) (*RecognizeResponse, []byte, error) {
+	ctx, cancel := param.withTimeout(ctx)
+	defer cancel()
+	ctx = withAudioDuration(ctx, &param.AudioSource)
+
+	timings := param.Timings
+	if timings != nil {
+		*timings = RequestTimings{}
+		start := time.Now()
+		defer func() { timings.Total = time.Since(start) }()
+		ctx = withTimings(ctx, timings)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if timings != nil {
+			// fetched before the upload, so that the upload does not include it
+			start := time.Now()
+			_, err := c.tp.Token(ctx)
+			timings.Auth += time.Since(start)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		start := time.Now()
+		resId, err := c.recognizeAsync(ctx, param)
+		if timings != nil {
+			timings.Upload += time.Since(start)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if param.Async {
+			return &RecognizeResponse{Id: resId, RequestConfig: param.config().submitted()}, nil, nil
+		}
+
+		resp, raw, err := c.receiveResultWithPolling(ctx, resId)
+		if err == nil {
+			resp.RequestConfig = param.config().submitted()
+			return resp, raw, nil
+		}
+		if attempt >= c.failedRetries || !c.resubmittable(param, err) {
+			return nil, nil, err
+		}
+	}
+}
+
+// resubmittable reports whether a job of param which failed with err may succeed when submitted again.
+func (c *restClient) resubmittable(param *RecognizeRequest, err error) bool {
+	if !errors.Is(err, ErrFailed) || !param.AudioSource.replayable() {
+		return false
+	}
+	var re *ResultError
+	if errors.As(err, &re) {
+		for _, code := range c.permanentFailureCodes {
+			if re.Code == code {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RecognizeToWriter recognizes param like Recognize, and writes the text of every utterance to w,
+// one per line, as soon as it is finalized. Utterances the server reports while the job is still
+// transcribing are written during polling; the rest is written once the job completes.
+func (c *restClient) RecognizeToWriter(ctx context.Context, param *RecognizeRequest, w io.Writer) (*RecognizeResponse, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.Done()
+
+	ctx, cancel := param.withTimeout(ctx)
+	defer cancel()
+	ctx = withAudioDuration(ctx, &param.AudioSource)
+
+	resId, err := c.recognizeAsync(ctx, param)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.receiveResultWithPolling(ctx, resId, 4*time.Second)
+	var final *RecognizeResponse
+	written := 0
+	err = c.poll(ctx, resId, func(ctx context.Context) (Status, bool, error) {
+		res, resByte, err := c.fetchResult(ctx, resId)
+		if err != nil {
+			return "", false, err
+		}
+		if res.Results != nil {
+			for ; written < len(res.Results.Utterances); written++ {
+				if _, err := fmt.Fprintln(w, res.Results.Utterances[written].Msg); err != nil {
+					return res.Status, false, err
+				}
+			}
+		}
+
+		switch res.Status {
+		case StatusCompleted:
+			final = res
+			return res.Status, true, nil
+		case StatusTranscribing:
+			return res.Status, false, nil
+		case StatusFailed:
+			return res.Status, false, res.failure()
+		default:
+			return res.Status, false, c.unexpectedResponse(resByte)
+		}
+	})
 	if err != nil {
 		return nil, err
 	}
-	return resp, nil
+	final.RequestConfig = param.config().submitted()
+	return final, nil
 }
 
-func (c *restClient) RecognizeAsync(ctx context.Context, param *RecognizeRequest) (ResultId, error) {
-	isPipeClose := false
+// RecognizeBatch recognizes reqs concurrently, at most ClientOption.BatchConcurrency at a time.
+// The returned results and errors are index-aligned with reqs regardless of completion order:
+// results[i] and errs[i] always belong to reqs[i], and exactly one of them is non-nil.
+func (c *restClient) RecognizeBatch(ctx context.Context, reqs []*RecognizeRequest) ([]*RecognizeResponse, []error) {
+	results := make([]*RecognizeResponse, len(reqs))
+	errs := make([]error, len(reqs))
 
-	r, w := io.Pipe()
-	writer := multipart.NewWriter(w)
-	defer func() {
-		if !isPipeClose {
-			r.Close()
-			w.Close()
-			writer.Close()
-		}
-	}()
+	limit := c.batchConcurrency
+	if limit < 0 {
+		limit = len(reqs)
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = c.Recognize(ctx, req)
+		}()
+	}
+	wg.Wait()
 
-	err := param.AudioSource.validate()
+	return results, errs
+}
+
+// Transcribe is a convenience wrapper around Recognize which builds the AudioSource from source.
+// source may be a file path (string), audio data ([]byte), or an io.Reader such as *os.File.
+func (c *restClient) Transcribe(ctx context.Context, config RecognitionConfig, source any) (*RecognizeResponse, error) {
+	audio, err := newRecognitionAudio(source)
 	if err != nil {
+		return nil, err
+	}
+	return c.Recognize(ctx, &RecognizeRequest{Config: config, AudioSource: audio})
+}
+
+func (c *restClient) RecognizeAsync(ctx context.Context, param *RecognizeRequest) (ResultId, error) {
+	if err := c.begin(); err != nil {
 		return "", err
 	}
+	defer c.inflight.Done()
 
-	errCh := make(chan error, 1)
-	defer close(errCh)
+	ctx, cancel := param.withTimeout(ctx)
+	defer cancel()
+	return c.recognizeAsync(ctx, param)
+}
 
-	go func() {
-		defer w.Close()
-		if err := createConfigField(writer, param.Config); err != nil {
-			errCh <- err
-			return
+func (c *restClient) recognizeAsync(ctx context.Context, param *RecognizeRequest) (ResultId, error) {
+	// nothing is opened or uploaded for a context which is already done
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if err := param.validate(); err != nil {
+		return "", err
+	}
+	if err := param.AudioSource.start(); err != nil {
+		return "", err
+	}
+	if err := param.checkWavFormat(ctx); err != nil {
+		return "", err
+	}
+	if c.dedupe && param.AudioSource.replayable() {
+		return c.submitOnce(ctx, param)
+	}
+	return c.submit(ctx, param)
+}
+
+// submit uploads validated param and returns the id of the new job.
+func (c *restClient) submit(ctx context.Context, param *RecognizeRequest) (ResultId, error) {
+	var (
+		body          io.ReadCloser
+		contentType   string
+		waitBody      func() error
+		contentLength int64
+		queryParams   = param.QueryParams
+	)
+	if size, ok := param.AudioSource.size(); ok && c.rawUploadThreshold > 0 && size > c.rawUploadThreshold {
+		// large audio: the body is the audio itself and the config travels in the query
+		config, err := encodeConfig(param.config())
+		if err != nil {
+			return "", err
 		}
-		if param.AudioSource.FilePath != "" {
-			if err := createFileFieldWithLocal(writer, param.AudioSource.FilePath); err != nil {
-				errCh <- err
-				return
-			}
-		} else {
-			if err := createFileFieldWithData(writer, param.AudioSource.Content); err != nil {
-				errCh <- err
-				return
-			}
+		audio, err := param.AudioSource.open(ctx)
+		if err != nil {
+			return "", err
 		}
-		if err := writer.Close(); err != nil {
-			errCh <- err
-			return
+		queryParams = url.Values{"config": {string(config)}}
+		for k, vs := range param.QueryParams {
+			queryParams[k] = append(queryParams[k], vs...)
+		}
+		body, contentType, waitBody, contentLength = audio, param.AudioSource.contentType(), func() error { return nil }, size
+	} else if content := param.AudioSource.Content; content != nil && len(content) <= c.inMemoryUploadThreshold {
+		// small clips: skip the pipe and goroutine, the body is built right away
+		pb, ct, err := newBufferedBody(param)
+		if err != nil {
+			return "", err
 		}
+		body, contentType, waitBody, contentLength = pb, ct, func() error { return nil }, int64(pb.Len())
+	} else {
+		r, w := io.Pipe()
+		writer := multipart.NewWriter(w)
+		// closing r stops the writer if the request ends before the body is consumed
+		defer r.Close()
 
-		errCh <- nil
-	}()
+		// buffered so that the writer never blocks (or panics) when the request fails early
+		errCh := make(chan error, 1)
+		go func() {
+			err := c.writeMultipart(ctx, writer, param)
+			w.CloseWithError(err)
+			errCh <- err
+		}()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, r)
+		body, contentType = r, writer.FormDataContentType()
+		waitBody = func() error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-errCh:
+				return err
+			}
+		}
+	}
+
+	req, err := c.newRequest(ctx, c.submitMethod, c.submitURL(queryParams), body)
 	if err != nil {
+		body.Close()
 		return "", err
 	}
-	req.Header.Add("Content-Type", writer.FormDataContentType())
+	if contentLength > 0 {
+		req.ContentLength = contentLength
+	}
+	req.Header.Add("Content-Type", contentType)
+	// the body is closed by the transport from here on
 	response, err := c.httpClient.Do(req)
 	if err != nil {
 		return "", err
 	}
-
-	r.Close()
-	isPipeClose = true
 	defer response.Body.Close()
 
-	select {
-	case <-ctx.Done():
-		return "", ctx.Err()
-	case err := <-errCh:
-		if err != nil {
-			return "", err
-		}
+	if r, ok := body.(*io.PipeReader); ok {
+		r.Close()
 	}
-	resByte, err := io.ReadAll(response.Body)
-	if err != nil {
+	if err := waitBody(); err != nil {
 		return "", err
 	}
+	resByte, err := c.readBody(response)
+	if err != nil {
+		return "", withRequestID(err, response)
+	}
 	if response.StatusCode != 200 {
-		return "", fmt.Errorf("server error : %d\n%s", response.StatusCode, string(resByte))
+		return "", c.apiError(response, resByte)
 	}
 	result := &RecognizeResponse{}
 	if err = json.Unmarshal(resByte, &result); err != nil {
-		return "", err
+		return "", withRequestID(err, response)
 	}
 
+	c.track(result.Id)
 	return result.Id, nil
 }
 
-func (c *restClient) ReceiveResult(ctx context.Context, resultId ResultId) (*RecognizeResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/"+string(resultId), nil)
+// writeMultipart writes the config and audio fields of param and closes writer.
+func (c *restClient) writeMultipart(ctx context.Context, writer *multipart.Writer, param *RecognizeRequest) error {
+	if err := createConfigField(writer, param.config()); err != nil {
+		return err
+	}
+	audio, err := param.AudioSource.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer audio.Close()
+
+	buf := make([]byte, c.copyBufferSize)
+	if err := createFileFieldWithReader(writer, param.AudioSource.fileName(), param.AudioSource.contentType(), audio, buf); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+var bodyBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// bufferedBody is a multipart body held in a pooled buffer, returned to the pool on Close.
+type bufferedBody struct {
+	*bytes.Reader
+	buf  *bytes.Buffer
+	once sync.Once
+}
+
+func (b *bufferedBody) Close() error {
+	b.once.Do(func() {
+		bodyBufferPool.Put(b.buf)
+	})
+	return nil
+}
+
+// newBufferedBody builds the multipart body of a request with in-memory Content.
+func newBufferedBody(param *RecognizeRequest) (*bufferedBody, string, error) {
+	buf := bodyBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	writer := multipart.NewWriter(buf)
+	err := createConfigField(writer, param.config())
+	if err == nil {
+		var fw io.Writer
+		if fw, err = createFilePart(writer, param.AudioSource.fileName(), param.AudioSource.contentType()); err == nil {
+			if _, err = fw.Write(param.AudioSource.Content); err == nil {
+				err = writer.Close()
+			}
+		}
+	}
+	if err != nil {
+		bodyBufferPool.Put(buf)
+		return nil, "", err
+	}
+	return &bufferedBody{Reader: bytes.NewReader(buf.Bytes()), buf: buf}, writer.FormDataContentType(), nil
+}
+
+// verifyBody checks body, read from resp with readErr, against the Content-Length and Content-MD5 headers.
+func verifyBody(resp *http.Response, body []byte, readErr error) error {
+	if errors.Is(readErr, io.ErrUnexpectedEOF) {
+		// the connection ended before Content-Length bytes were read
+		return fmt.Errorf("%w: %w", ErrCorruptResponse, readErr)
+	}
+	if readErr != nil {
+		return readErr
+	}
+	// a body decompressed by the transport no longer matches the headers
+	if resp.Uncompressed {
+		return nil
+	}
+	if resp.ContentLength >= 0 && int64(len(body)) != resp.ContentLength {
+		return fmt.Errorf("%w: read %d bytes, expected %d", ErrCorruptResponse, len(body), resp.ContentLength)
+	}
+	if want := resp.Header.Get("Content-MD5"); want != "" {
+		sum := md5.Sum(body)
+		if got := base64.StdEncoding.EncodeToString(sum[:]); got != want {
+			return fmt.Errorf("%w: Content-MD5 is %s, expected %s", ErrCorruptResponse, got, want)
+		}
+	}
+	return nil
+}
+
+// readBody reads the body of resp, failing with ErrResponseTooLarge beyond maxResponseBytes.
+func (c *restClient) readBody(resp *http.Response) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(b)) > c.maxResponseBytes {
+		return nil, ErrResponseTooLarge
+	}
+	return b, nil
+}
 
-	response, err := c.httpClient.Do(req)
+// newRequest creates a request carrying the headers stored in ctx.
+// An Accept-Language header from ctx takes precedence over the client's.
+func (c *restClient) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	applyContextHeaders(ctx, req)
+	if req.Header.Get("Accept-Language") == "" {
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+	}
+	return req, nil
+}
+
+const resultIdPlaceholder = "{id}"
+
+// submitURL returns the submit url with the client and request query parameters appended.
+func (c *restClient) submitURL(params url.Values) string {
+	base := c.endpoint
+	if c.submitPath != "" {
+		base = withPath(c.endpoint, c.submitPath)
+	}
+	if len(c.queryParams) == 0 && len(params) == 0 {
+		return base
+	}
+	query := url.Values{}
+	for _, p := range []url.Values{c.queryParams, params} {
+		for k, vs := range p {
+			query[k] = append(query[k], vs...)
+		}
+	}
+	return base + "?" + query.Encode()
+}
+
+// resultURL returns the url of the result of the job.
+func (c *restClient) resultURL(resultId ResultId) string {
+	if c.resultPathTemplate == "" {
+		return c.resultEndpoint + "/" + string(resultId)
+	}
+	return withPath(c.resultEndpoint, strings.ReplaceAll(c.resultPathTemplate, resultIdPlaceholder, url.PathEscape(string(resultId))))
+}
+
+// withPath returns endpoint with its path replaced by path.
+func withPath(endpoint, path string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		// malformed endpoints are reported by the request itself
+		return endpoint
+	}
+	u.Path = path
+	u.RawPath = ""
+	return u.String()
+}
+
+func (c *restClient) ReceiveResult(ctx context.Context, resultId ResultId) (*RecognizeResponse, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.Done()
+	res, _, err := c.receiveResult(ctx, resultId)
+	return res, err
+}
+
+// ReceiveResultInto unmarshals the raw result of a completed job into v, for callers with their own model.
+// Like ReceiveResult, it returns ErrNotFinish while the job is in progress and leaves v untouched.
+func (c *restClient) ReceiveResultInto(ctx context.Context, resultId ResultId, v any) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.inflight.Done()
+	_, raw, err := c.receiveResult(ctx, resultId)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// ReceiveResultSince returns the result of the job with only the utterances after cursor, the number of
+// utterances already seen, along with the cursor for the next call. Start with a cursor of zero.
+//
+// Unlike ReceiveResult, it does not fail while the job is in progress: it returns the utterances
+// transcribed so far with StatusTranscribing, and the job is done once the returned Status is
+// StatusCompleted. A failed job returns an error matching ErrFailed.
+//
+// The server has no delta API, so the full result is still downloaded; only the returned response
+// is trimmed. A cursor beyond the current utterances returns none and leaves the cursor unchanged.
+func (c *restClient) ReceiveResultSince(ctx context.Context, resultId ResultId, cursor int) (*RecognizeResponse, int, error) {
+	if err := c.begin(); err != nil {
+		return nil, cursor, err
+	}
+	defer c.inflight.Done()
+	if err := ctx.Err(); err != nil {
+		return nil, cursor, err
+	}
+
+	res, _, err := c.fetchResult(ctx, resultId)
+	if err != nil {
+		return nil, cursor, err
+	}
+	if res.Status == StatusFailed {
+		return nil, cursor, res.failure()
+	}
+	if res.Results == nil {
+		return res, cursor, nil
+	}
+	utterances := res.Results.Utterances
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor >= len(utterances) {
+		res.Results.Utterances = nil
+		return res, cursor, nil
+	}
+	res.Results.Utterances = utterances[cursor:]
+	return res, len(utterances), nil
+}
+
+// CacheStats returns the statistics of the completed result cache.
+// It returns zero stats when the cache is disabled.
+func (c *restClient) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.snapshot()
+}
+
+// receiveResult returns the completed result of the job along with its raw body.
+func (c *restClient) receiveResult(ctx context.Context, resultId ResultId) (*RecognizeResponse, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if c.cache != nil {
+		if raw, ok := c.cache.get(resultId); ok {
+			result := &RecognizeResponse{}
+			if err := json.Unmarshal(raw, result); err != nil {
+				return nil, nil, err
+			}
+			return result, raw, nil
+		}
+	}
+
+	result, resByte, err := c.fetchResult(ctx, resultId)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch result.Status {
+	case StatusCompleted:
+		// only completed results are immutable
+		if c.cache != nil {
+			c.cache.add(resultId, resByte)
+		}
+		return result, resByte, nil
+	case StatusTranscribing:
+		return nil, nil, ErrNotFinish
+	case StatusFailed:
+		return nil, nil, result.failure()
+	default:
+		return nil, nil, c.unexpectedResponse(resByte)
+	}
+}
+
+// fetchResult returns the parsed result of the job whatever its status, along with the raw body.
+func (c *restClient) fetchResult(ctx context.Context, resultId ResultId) (*RecognizeResponse, []byte, error) {
+	response, err := c.getResult(ctx, resultId)
 	if err != nil {
-		return nil, fmt.Errorf("server request error")
+		return nil, nil, err
 	}
 	defer response.Body.Close()
 
 	result := &RecognizeResponse{}
-	resByte, err := io.ReadAll(response.Body)
+	resByte, err := c.readBody(response)
+	if c.verifyResults {
+		err = verifyBody(response, resByte, err)
+	}
 	if err != nil {
-		return nil, err
+		return nil, nil, withRequestID(err, response)
 	}
 
 	if err := json.Unmarshal(resByte, &result); err != nil {
+		return nil, nil, withRequestID(err, response)
+	}
+	result.ServerRequestID = response.Header.Get(ServerRequestIDHeader)
+	c.observe(resultId, result.Status)
+	return result, resByte, nil
+}
+
+// getResult requests the result of the job and returns the response only if it succeeded.
+// Failures worth retrying are wrapped in retryableError.
+func (c *restClient) getResult(ctx context.Context, resultId ResultId) (*http.Response, error) {
+	var (
+		response *http.Response
+		err      error
+	)
+	if c.hedgeDelay > 0 {
+		response, err = c.getHedged(ctx, c.resultURL(resultId))
+	} else {
+		var req *http.Request
+		if req, err = c.newRequest(ctx, http.MethodGet, c.resultURL(resultId), nil); err != nil {
+			return nil, err
+		}
+		response, err = c.httpClient.Do(req)
+	}
+	if err != nil {
+		// report cancellation as is rather than as a request failure
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		err = fmt.Errorf("server request error: %w", err)
+		if c.isRetryable(nil, err) {
+			return nil, &retryableError{err}
+		}
 		return nil, err
 	}
-	switch result.Status {
-	case "completed":
-		return result, nil
-	case "transcribing":
-		return nil, ErrNotFinish
-	case "failed":
-		return nil, ErrFailed
-	default:
-		return nil, fmt.Errorf("server response error : %s", string(resByte))
+	recordRetryAfter(ctx, response)
+
+	if response.StatusCode == http.StatusOK {
+		return response, nil
 	}
+	defer response.Body.Close()
+
+	// the job was deleted (or expired) after it was submitted
+	if response.StatusCode == http.StatusNotFound {
+		c.untrack(resultId)
+		return nil, withRequestID(ErrResultGone, response)
+	}
+	resByte, err := c.readBody(response)
+	if err != nil {
+		return nil, withRequestID(err, response)
+	}
+	err = c.apiError(response, resByte)
+	if c.isRetryable(response, nil) {
+		return nil, &retryableError{err}
+	}
+	return nil, err
 }
 
-func (c *restClient) receiveResultWithPolling(ctx context.Context, resultId ResultId, delay time.Duration) (*RecognizeResponse, error) {
+// getHedged GETs url and, if no response arrived within hedgeDelay, sends the same request again.
+// The first response wins and the other request is cancelled. A request failing with a retryable
+// transport error leaves the other one running; any other error is returned right away.
+// It must only be used for idempotent requests.
+func (c *restClient) getHedged(ctx context.Context, url string) (*http.Response, error) {
+	type attempt struct {
+		i    int
+		resp *http.Response
+		err  error
+	}
+	var cancels []context.CancelFunc
+	attempts := make(chan attempt, 2)
+	launch := func() error {
+		actx, cancel := context.WithCancel(ctx)
+		req, err := c.newRequest(actx, http.MethodGet, url, nil)
+		if err != nil {
+			cancel()
+			return err
+		}
+		i := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := c.httpClient.Do(req)
+			attempts <- attempt{i, resp, err}
+		}()
+		return nil
+	}
+
+	if err := launch(); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	pending := 1
+	// returned along with the error of the first request when the hedged one could not be sent
+	var hedgeErr error
 	for {
 		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(delay):
-			res, err := c.ReceiveResult(ctx, resultId)
-			if err != nil {
-				if errors.Is(err, ErrNotFinish) {
-					continue
+		case <-timer.C:
+			if err := launch(); err != nil {
+				hedgeErr = fmt.Errorf("hedged request: %w", err)
+			} else {
+				pending++
+			}
+		case a := <-attempts:
+			pending--
+			if a.err != nil && pending > 0 && c.isRetryable(nil, a.err) {
+				continue
+			}
+			for i, cancel := range cancels {
+				if i != a.i {
+					cancel()
 				}
-				return nil, err
 			}
-
-			if res != nil {
-				return res, nil
+			// the losing response, if any, is discarded when it arrives
+			go func(n int) {
+				for ; n > 0; n-- {
+					if lost := <-attempts; lost.resp != nil {
+						lost.resp.Body.Close()
+					}
+				}
+			}(pending)
+			if a.err != nil {
+				cancels[a.i]()
+				if hedgeErr != nil {
+					return nil, errors.Join(a.err, hedgeErr)
+				}
+				return nil, a.err
 			}
-			return nil, errors.New("nil response return")
+			a.resp.Body = &cancelOnClose{ReadCloser: a.resp.Body, cancel: cancels[a.i]}
+			return a.resp, nil
 		}
 	}
 }
 
-func createFileFieldWithLocal(writer *multipart.Writer, filePath string) error {
-	audiofile, err := os.Open(filePath)
-	if err != nil {
+// cancelOnClose cancels the context of a request when its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// isRetryable applies the retry policy of the client.
+func (c *restClient) isRetryable(resp *http.Response, err error) bool {
+	if c.retryPolicy != nil {
+		return c.retryPolicy(resp, err)
+	}
+	return isRetryable(resp, err)
+}
+
+// track records a job submitted through this client.
+func (c *restClient) track(resultId ResultId) {
+	c.mu.Lock()
+	c.jobs[resultId] = ""
+	c.mu.Unlock()
+}
+
+func (c *restClient) untrack(resultId ResultId) {
+	c.mu.Lock()
+	if key := c.jobs[resultId]; key != "" {
+		delete(c.submissions, key)
+	}
+	delete(c.jobs, resultId)
+	c.mu.Unlock()
+}
+
+// observe stops tracking a job once it is seen in a terminal state.
+func (c *restClient) observe(resultId ResultId, status Status) {
+	if status == StatusCompleted || status == StatusFailed {
+		c.untrack(resultId)
+	}
+}
+
+// InFlight returns the ids of jobs submitted through this client whose completion or failure
+// has not been observed yet, in no particular order. Jobs submitted through other clients
+// (or other processes) are not included, even for the same credentials.
+//
+// A job is only dropped once this client observes it finished, so jobs submitted with
+// RecognizeAsync and polled elsewhere stay tracked for the lifetime of the client; call Forget
+// for them to release their memory.
+func (c *restClient) InFlight() []ResultId {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]ResultId, 0, len(c.jobs))
+	for id := range c.jobs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Forget stops tracking the jobs, as if they were observed finished: they are dropped from
+// InFlight, and identical submissions are no longer deduplicated against them.
+// The jobs themselves are left untouched on the server.
+func (c *restClient) Forget(resultIds ...ResultId) {
+	for _, id := range resultIds {
+		c.untrack(id)
+	}
+}
+
+// CancelAll deletes every job returned by InFlight. It may be called after Close or Shutdown
+// to clean up jobs which will not be polled anymore. Jobs which could not be deleted are
+// reported together in the returned error and stay in InFlight.
+func (c *restClient) CancelAll(ctx context.Context) error {
+	var errs []error
+	for _, id := range c.InFlight() {
+		if err := c.deleteResult(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteResult deletes the job and its result from the server.
+// Deleting a job which no longer exists is not an error.
+func (c *restClient) DeleteResult(ctx context.Context, resultId ResultId) error {
+	if err := c.begin(); err != nil {
 		return err
 	}
-	defer audiofile.Close()
+	defer c.inflight.Done()
+	return c.deleteResult(ctx, resultId)
+}
 
-	fw, err := writer.CreateFormFile("file", audiofile.Name())
+func (c *restClient) deleteResult(ctx context.Context, resultId ResultId) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.resultURL(resultId), nil)
 	if err != nil {
 		return err
 	}
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("server request error: %w", err)
+	}
+	defer response.Body.Close()
 
-	if _, err = io.Copy(fw, audiofile); err != nil {
-		return err
+	resByte, err := c.readBody(response)
+	if err != nil {
+		return withRequestID(err, response)
 	}
-	return nil
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		c.untrack(resultId)
+		if c.cache != nil {
+			c.cache.remove(resultId)
+		}
+		return nil
+	}
+	return c.apiError(response, resByte)
+}
+
+// GetStatus returns only the status of the job.
+// The API has no status endpoint, so the full result is still downloaded; only the status field
+// is decoded, which saves parsing large results but not bandwidth. Polling within the client
+// therefore fetches full results instead, so that a completed result is not downloaded twice.
+func (c *restClient) GetStatus(ctx context.Context, resultId ResultId) (Status, error) {
+	if err := c.begin(); err != nil {
+		return "", err
+	}
+	defer c.inflight.Done()
+	return c.getStatus(ctx, resultId)
+}
+
+func (c *restClient) getStatus(ctx context.Context, resultId ResultId) (Status, error) {
+	response, err := c.getResult(ctx, resultId)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	// like readBody, a body reaching one byte past maxResponseBytes is too large
+	body := &io.LimitedReader{R: response.Body, N: c.maxResponseBytes + 1}
+	status, err := decodeStatus(body)
+	// the rest of the body is drained so that the connection can be reused
+	io.Copy(io.Discard, body)
+	if body.N == 0 {
+		return "", ErrResponseTooLarge
+	}
+	if err != nil {
+		return "", err
+	}
+	c.observe(resultId, status)
+	return status, nil
+}
+
+// decodeStatus reads the top-level "status" field of a result without decoding the rest of it.
+func decodeStatus(r io.Reader) (Status, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return "", err
+	} else if tok != json.Delim('{') {
+		return "", fmt.Errorf("unexpected response token %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if key, _ := tok.(string); key == "status" {
+			var status Status
+			if err := dec.Decode(&status); err != nil {
+				return "", err
+			}
+			return status, nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("status is missing in server response")
+}
+
+// WaitUntil polls the status of the job until pred returns true for it or the job reaches a terminal state,
+// and returns the result at that point.
+//
+// pred sees every polled status, including terminal ones. If pred accepts the status, the result is returned
+// with a nil error even when the job failed. Otherwise polling stops at the terminal states: a completed job
+// returns its result with a nil error, and a failed job returns an error matching ErrFailed, which is
+// a *ResultError when the server reported the reason.
+// Statuses unknown to the SDK are treated as in progress.
+func (c *restClient) WaitUntil(ctx context.Context, resultId ResultId, pred func(Status) bool) (*RecognizeResponse, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.Done()
+	res, _, err := c.waitUntil(ctx, resultId, pred)
+	return res, err
 }
 
-func createFileFieldWithData(writer *multipart.Writer, contents []byte) error {
-	fw, err := writer.CreateFormFile("file", "rtzr-default-audiofile")
+func (c *restClient) waitUntil(ctx context.Context, resultId ResultId, pred func(Status) bool) (*RecognizeResponse, []byte, error) {
+	if c.cache != nil {
+		if raw, ok := c.cache.get(resultId); ok {
+			result := &RecognizeResponse{}
+			if err := json.Unmarshal(raw, result); err != nil {
+				return nil, nil, err
+			}
+			return result, raw, nil
+		}
+	}
+
+	// every poll downloads the full result, since the API has no status endpoint, so the last one is
+	// kept rather than downloaded again
+	var (
+		res      *RecognizeResponse
+		raw      []byte
+		accepted bool
+	)
+	err := c.poll(ctx, resultId, func(ctx context.Context) (Status, bool, error) {
+		var err error
+		res, raw, err = c.fetchResult(ctx, resultId)
+		if err != nil {
+			return "", false, err
+		}
+		accepted = pred(res.Status)
+		return res.Status, accepted || res.Status == StatusCompleted || res.Status == StatusFailed, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.Status == StatusCompleted && c.cache != nil {
+		// only completed results are immutable
+		c.cache.add(resultId, raw)
+	}
+	if !accepted && res.Status == StatusFailed {
+		return nil, nil, res.failure()
+	}
+	return res, raw, nil
+}
+
+// receiveResultWithPolling polls until the job is completed or failed.
+func (c *restClient) receiveResultWithPolling(ctx context.Context, resultId ResultId) (*RecognizeResponse, []byte, error) {
+	return c.waitUntil(ctx, resultId, func(status Status) bool {
+		return status == StatusCompleted
+	})
+}
+
+// poll calls check right away, then after every polling delay, until it reports done or fails.
+// Up to pollRetry consecutive transient failures are tolerated; other errors abort immediately.
+// check reports the status it observed, if any, for poll logging.
+func (c *restClient) poll(ctx context.Context, resultId ResultId, check func(ctx context.Context) (status Status, done bool, err error)) error {
+	logPolls := c.logPolls || pollLoggingFromContext(ctx)
+	onPoll, audioDuration := pollCallbackFromContext(ctx), audioDurationFromContext(ctx)
+	timings := timingsFromContext(ctx)
+	start := time.Now()
+	failures := 0
+	delay := c.polling.Interval
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// the delay hinted by the server for this attempt, see recordRetryAfter
+		var retryAfter time.Duration
+		checkStart := time.Now()
+		status, done, err := check(context.WithValue(ctx, retryAfterKey{}, &retryAfter))
+		if timings != nil {
+			timings.Polls++
+			timings.Polling += time.Since(checkStart)
+		}
+		if logPolls {
+			c.logPoll(ctx, resultId, attempt, time.Since(start), status, err)
+		}
+		if onPoll != nil {
+			onPoll(newPollProgress(resultId, attempt, time.Since(start), status, audioDuration))
+		}
+		switch {
+		case err != nil:
+			if !isRetryableError(err) || failures >= c.pollRetry {
+				return err
+			}
+			failures++
+		case done:
+			return nil
+		default:
+			failures = 0
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = c.polling.RetryAfter(retryAfter)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = c.polling.Next(delay)
+	}
+}
+
+type retryAfterKey struct{}
+
+// recordRetryAfter stores the Retry-After header of resp, in seconds or as an HTTP date, for the
+// poll which made the request with ctx.
+func recordRetryAfter(ctx context.Context, resp *http.Response) {
+	hint, ok := ctx.Value(retryAfterKey{}).(*time.Duration)
+	if !ok {
+		return
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		*hint = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		*hint = time.Until(t)
+	}
+}
+
+func newPollProgress(resultId ResultId, attempt int, elapsed time.Duration, status Status, audioDuration time.Duration) PollProgress {
+	p := PollProgress{ResultId: resultId, Attempt: attempt, Elapsed: elapsed, Status: status, AudioDuration: audioDuration, ETA: -1}
+	switch {
+	case status == StatusCompleted:
+		p.ETA = 0
+	case audioDuration > 0:
+		p.ETA = max(audioDuration-elapsed, 0)
+	}
+	return p
+}
+
+// logPoll logs a poll attempt at slog.LevelInfo.
+func (c *restClient) logPoll(ctx context.Context, resultId ResultId, attempt int, elapsed time.Duration, status Status, err error) {
+	attrs := []slog.Attr{
+		slog.String("result_id", string(resultId)),
+		slog.Int("attempt", attempt),
+		slog.Duration("elapsed", elapsed),
+		slog.String("status", string(status)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "rtzr poll", attrs...)
+}
+
+func createFileFieldWithReader(writer *multipart.Writer, fileName, contentType string, r io.Reader, buf []byte) error {
+	fw, err := createFilePart(writer, fileName, contentType)
 	if err != nil {
 		return err
 	}
 
-	buffer := bytes.NewBuffer(contents)
-	if _, err = io.Copy(fw, buffer); err != nil {
+	if _, err = io.CopyBuffer(fw, struct{ io.Reader }{r}, buf); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// createFilePart creates the "file" part like multipart.Writer.CreateFormFile, with contentType.
+func createFilePart(writer *multipart.Writer, fileName, contentType string) (io.Writer, error) {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(fileName)))
+	h.Set("Content-Type", contentType)
+	return writer.CreatePart(h)
+}
+
+// createConfigField writes the resolved config as compact JSON.
 func createConfigField(writer *multipart.Writer, config RecognitionConfig) error {
 	fw, err := writer.CreateFormField("config")
 	if err != nil {
 		return err
 	}
 
-	j, err := json.Marshal(config)
+	j, err := encodeConfig(config)
 	if err != nil {
 		return err
 	}
@@ -249,3 +1417,16 @@ func createConfigField(writer *multipart.Writer, config RecognitionConfig) error
 
 	return nil
 }
+
+// encodeConfig returns the resolved config as compact JSON. HTML characters are not escaped,
+// so that keywords containing '&', '<' or '>' reach the server as written.
+func encodeConfig(config RecognitionConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(config.resolve()); err != nil {
+		return nil, err
+	}
+	// Encode terminates the value with a newline
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,178 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vito-ai/go-sdk/auth"
+	"github.com/vito-ai/go-sdk/auth/option"
+)
+
+func TestNewRestClientAuthErrors(t *testing.T) {
+	t.Setenv("RTZR_CLIENT_ID", "")
+	t.Setenv("RTZR_CLIENT_SECRET", "")
+	tests := []struct {
+		name string
+		opt  *option.ClientOption
+		want error
+	}{
+		{"missing credentials", &option.ClientOption{}, auth.ErrInvalidCredentials},
+		{"invalid token url", &option.ClientOption{ClientId: "id", ClientSecret: "secret", TokenURL: "not a url"}, auth.ErrInvalidTokenURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRestClient(tt.opt); !errors.Is(err, tt.want) {
+				t.Fatalf("NewRestClient() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkWriteMultipart(b *testing.B) {
+	audio := bytes.Repeat([]byte{0x55}, 8<<20)
+	path := filepath.Join(b.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, audio, 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, size := range []int{32 << 10, 256 << 10, 1 << 20} {
+		b.Run(fmt.Sprintf("buffer=%dKB", size>>10), func(b *testing.B) {
+			c := &restClient{copyBufferSize: size}
+			param := &RecognizeRequest{AudioSource: RecognitionAudio{FilePath: path}}
+			b.SetBytes(int64(len(audio)))
+			for i := 0; i < b.N; i++ {
+				if err := c.writeMultipart(context.Background(), multipart.NewWriter(io.Discard), param); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSubmitSmallContent compares uploading small in-memory clips through the buffered body
+// against streaming them through a pipe, against a local server which discards the upload.
+func BenchmarkSubmitSmallContent(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == "/v1/authenticate" {
+			fmt.Fprintf(w, `{"access_token":"token","expire_at":%d}`, time.Now().Add(time.Hour).Unix())
+			return
+		}
+		io.WriteString(w, `{"id":"job"}`)
+	}))
+	defer srv.Close()
+
+	for _, size := range []int{4 << 10, 64 << 10} {
+		for _, path := range []struct {
+			name      string
+			threshold int
+		}{
+			{"buffered", 100 << 10},
+			{"pipe", -1},
+		} {
+			b.Run(fmt.Sprintf("%s/size=%dKB", path.name, size>>10), func(b *testing.B) {
+				c, err := NewRestClient(&option.ClientOption{
+					ClientId:                "id",
+					ClientSecret:            "secret",
+					Endpoint:                srv.URL + "/v1/transcribe",
+					TokenURL:                srv.URL + "/v1/authenticate",
+					InMemoryUploadThreshold: path.threshold,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				defer c.Close()
+				param := &RecognizeRequest{AudioSource: RecognitionAudio{Content: bytes.Repeat([]byte{0x55}, size)}}
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := c.submit(context.Background(), param); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCreateConfigFieldNoHTMLEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := createConfigField(writer, RecognitionConfig{Keywords: []string{"R&D", "<연구>"}}); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := form.Value["config"][0]
+	if !strings.Contains(got, `"keywords":["R&D","<연구>"]`) {
+		t.Fatalf("config field = %s, want the keywords unescaped", got)
+	}
+	if strings.ContainsAny(got, "\n ") {
+		t.Fatalf("config field = %q, want compact JSON", got)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestGetHedgedFailsFast(t *testing.T) {
+	errRejected := errors.New("rejected")
+	var calls atomic.Int32
+	hedged := make(chan struct{})
+	c := &restClient{
+		hedgeDelay:  10 * time.Millisecond,
+		retryPolicy: func(*http.Response, error) bool { return false },
+		httpClient: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				// the first request fails once the hedge is in flight
+				<-hedged
+				return nil, errRejected
+			}
+			close(hedged)
+			select {
+			case <-r.Context().Done():
+				return nil, r.Context().Err()
+			case <-time.After(5 * time.Second):
+				return nil, errors.New("hedged request was not cancelled")
+			}
+		})},
+	}
+
+	start := time.Now()
+	_, err := c.getHedged(context.Background(), "http://example.com/result")
+	if !errors.Is(err, errRejected) {
+		t.Fatalf("getHedged() error = %v, want %v", err, errRejected)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("getHedged() waited %v for the hedged request", elapsed)
+	}
+}
+
+func TestIsRetryableAuthErrors(t *testing.T) {
+	for _, err := range []error{auth.ErrInvalidCredentials, auth.ErrInvalidTokenURL} {
+		wrapped := fmt.Errorf("server request error: %w", fmt.Errorf("%w: rejected", err))
+		if isRetryable(nil, wrapped) {
+			t.Errorf("isRetryable(%v) = true, want false", wrapped)
+		}
+	}
+	if !isRetryable(nil, errors.New("connection reset")) {
+		t.Error("isRetryable(connection reset) = false, want true")
+	}
+}
@@ -0,0 +1,205 @@
+// Package speechtest provides a fake RTZR STT server for testing code which uses the speech package.
+//
+// The fake implements the authenticate, submit, poll and delete endpoints of the REST API:
+//
+//	srv := speechtest.NewServer()
+//	defer srv.Close()
+//	client, err := speech.NewRestClient(srv.ClientOption())
+package speechtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vito-ai/go-sdk/auth/option"
+	"github.com/vito-ai/go-sdk/speech"
+)
+
+const (
+	// TokenPath is the path of the authenticate endpoint.
+	TokenPath = "/v1/authenticate"
+	// TranscribePath is the path of the submit endpoint; results are served under TranscribePath + "/{id}".
+	TranscribePath = "/v1/transcribe"
+
+	accessToken = "speechtest-token"
+)
+
+// Server is a fake RTZR STT server.
+//
+// Its exported fields may be changed before the first request is made; they must not be
+// changed while requests are in flight.
+type Server struct {
+	*httptest.Server
+
+	// Delay is how long a submitted job reports "transcribing" before it completes.
+	Delay time.Duration
+	// Result is served for every completed job. Defaults to a single utterance "hello".
+	Result *speech.Results
+	// Intercept, when set, is called before a request is handled. If it returns true, it has
+	// written the response itself and the request is not handled further. It allows injecting
+	// errors and custom responses.
+	Intercept func(w http.ResponseWriter, r *http.Request) bool
+
+	mu   sync.Mutex
+	jobs map[speech.ResultId]*job
+}
+
+type job struct {
+	submittedAt time.Time
+	config      *speech.RecognitionConfig
+}
+
+// NewServer starts and returns a fake server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		Result: &speech.Results{
+			Utterances: []*speech.Utterance{{StartAt: 0, Duration: 1000, Msg: "hello"}},
+		},
+		jobs: make(map[speech.ResultId]*job),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ClientOption returns options pointing a client at the server, with fake credentials
+// and a short polling interval.
+func (s *Server) ClientOption() *option.ClientOption {
+	return &option.ClientOption{
+		ClientId:     "speechtest-id",
+		ClientSecret: "speechtest-secret",
+		Endpoint:     s.URL + TranscribePath,
+		TokenURL:     s.URL + TokenPath,
+		Polling:      option.PollingConfig{Interval: 10 * time.Millisecond},
+	}
+}
+
+// Jobs returns the number of submitted jobs which have not been deleted.
+func (s *Server) Jobs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Intercept != nil && s.Intercept(w, r) {
+		return
+	}
+	if r.URL.Path == TokenPath {
+		s.authenticate(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+accessToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.URL.Path == TranscribePath && r.Method == http.MethodPost:
+		s.submit(w, r)
+	case strings.HasPrefix(r.URL.Path, TranscribePath+"/"):
+		id := speech.ResultId(strings.TrimPrefix(r.URL.Path, TranscribePath+"/"))
+		switch r.Method {
+		case http.MethodGet:
+			s.result(w, id)
+		case http.MethodDelete:
+			s.delete(w, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.PostFormValue("client_id") == "" || r.PostFormValue("client_secret") == "" {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, map[string]any{
+		"access_token": accessToken,
+		"expire_at":    time.Now().Add(time.Hour).Unix(),
+	})
+}
+
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
+	var rawConfig string
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		// raw body upload with the audio's content type, see option.ClientOption.RawUploadThreshold
+		rawConfig = r.URL.Query().Get("config")
+	} else {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, "invalid multipart body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, _, err := r.FormFile("file"); err != nil {
+			http.Error(w, "missing file field", http.StatusBadRequest)
+			return
+		}
+		rawConfig = r.FormValue("config")
+	}
+	config := &speech.RecognitionConfig{}
+	if err := json.Unmarshal([]byte(rawConfig), config); err != nil {
+		http.Error(w, "invalid config field: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := newID()
+	s.mu.Lock()
+	s.jobs[id] = &job{submittedAt: time.Now(), config: config}
+	s.mu.Unlock()
+
+	writeJSON(w, &speech.RecognizeResponse{Id: id})
+}
+
+func (s *Server) result(w http.ResponseWriter, id speech.ResultId) {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	resp := &speech.RecognizeResponse{Id: id, Status: speech.StatusTranscribing}
+	if time.Since(j.submittedAt) >= s.Delay {
+		resp.Status = speech.StatusCompleted
+		resp.Results = s.Result
+		resp.Config = j.config
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) delete(w http.ResponseWriter, id speech.ResultId) {
+	s.mu.Lock()
+	_, ok := s.jobs[id]
+	delete(s.jobs, id)
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newID() speech.ResultId {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return speech.ResultId(hex.EncodeToString(b))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
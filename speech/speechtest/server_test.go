@@ -0,0 +1,70 @@
+package speechtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vito-ai/go-sdk/speech"
+	"github.com/vito-ai/go-sdk/speech/speechtest"
+)
+
+func TestRoundTrip(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	srv.Delay = 30 * time.Millisecond
+
+	client, err := speech.NewRestClient(srv.ClientOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	id, err := client.RecognizeAsync(ctx, &speech.RecognizeRequest{
+		Config:      speech.RecognitionConfig{Domain: "CALL"},
+		AudioSource: speech.RecognitionAudio{Content: []byte("audio")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := client.GetStatus(ctx, id); err != nil || status != speech.StatusTranscribing {
+		t.Fatalf("GetStatus() = %q, %v, want %q", status, err, speech.StatusTranscribing)
+	}
+
+	resp, err := client.WaitUntil(ctx, id, func(s speech.Status) bool { return s == speech.StatusCompleted })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Id != id || resp.Status != speech.StatusCompleted {
+		t.Fatalf("WaitUntil() = %s %q, want %s %q", resp.Id, resp.Status, id, speech.StatusCompleted)
+	}
+	if got := resp.FullTranscript(); got != "hello" {
+		t.Fatalf("FullTranscript() = %q, want %q", got, "hello")
+	}
+	if resp.Config == nil || resp.Config.Domain != "CALL" {
+		t.Fatalf("the server did not receive the config, got %+v", resp.Config)
+	}
+}
+
+func TestRawUpload(t *testing.T) {
+	srv := speechtest.NewServer()
+	defer srv.Close()
+	opt := srv.ClientOption()
+	opt.RawUploadThreshold = 4
+	client, err := speech.NewRestClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []speech.AudioFormat{"", speech.AudioFormatFLAC} {
+		resp, err := client.Recognize(context.Background(), &speech.RecognizeRequest{
+			Config:      speech.RecognitionConfig{Domain: "CALL"},
+			AudioSource: speech.RecognitionAudio{Content: []byte("raw audio"), Format: format},
+		})
+		if err != nil {
+			t.Fatalf("format %q: %v", format, err)
+		}
+		if resp.Config == nil || resp.Config.Domain != "CALL" || resp.Config.Encoding != format {
+			t.Fatalf("format %q: the server received config %+v", format, resp.Config)
+		}
+	}
+}
@@ -2,14 +2,16 @@ package speech
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
 	pb "github.com/vito-ai/go-genproto/vito-openapi/stt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/vito-ai/go-sdk/auth"
 	"github.com/vito-ai/go-sdk/auth/option"
@@ -37,7 +39,15 @@ func NewStreamingClient(ctx context.Context, cliopts *option.ClientOption) (*gRP
 	}
 
 	var dialOpts []grpc.DialOption
-	dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(cliopts.TLSConfig())))
+	if cliopts.StreamingKeepalive > 0 {
+		// 스트림이 없는 유휴 연결에도 ping을 보내 중간 장비가 연결을 끊지 않도록 합니다.
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cliopts.StreamingKeepalive,
+			Timeout:             cliopts.GetStreamingKeepaliveTimeout(),
+			PermitWithoutStream: true,
+		}))
+	}
 
 	conn, err := grpc.NewClient(cliopts.GetStreamingEndpoint(), dialOpts...)
 	if err != nil {
@@ -60,7 +70,8 @@ func (c *gRPCClient) StreamingRecognize(ctx context.Context) (pb.OnlineDecoder_D
 	if err != nil {
 		return nil, err
 	}
-	md := metadata.Pairs("authorization", fmt.Sprintf("%s %v", "bearer", token.AccessToken))
+	md := contextMetadata(ctx)
+	md.Set("authorization", fmt.Sprintf("%s %v", "bearer", token.AccessToken))
 	ctxWithAuth := metautils.NiceMD(md).ToOutgoing(ctx)
 
 	stream, err := c.client.Decode(ctxWithAuth)
@@ -70,6 +81,76 @@ func (c *gRPCClient) StreamingRecognize(ctx context.Context) (pb.OnlineDecoder_D
 	return stream, nil
 }
 
+// Drain는 stream의 음성 전송을 종료(CloseSend)하고, 서버가 stream을 끝낼 때까지 남은 결과를 읽어서 버립니다.
+// 결과를 끝까지 읽지 않고 종료할 때 goroutine 누수를 막기 위해 사용합니다. 클라이언트의 연결은 다른 스트림과
+// 공유하므로 닫지 않습니다.
+//
+// ctx가 끝나면 남은 결과를 기다리지 않고 ctx의 에러를 반환합니다. 이 경우 stream을 연 context를 취소해야
+// stream이 정리됩니다.
+//
+// 올바른 종료 순서는 다음과 같습니다.
+//  1. 음성 전송을 멈춥니다. (Send를 더 이상 호출하지 않습니다.)
+//  2. 남은 결과가 필요하면 stream.CloseSend() 후 io.EOF가 반환될 때까지 Recv를 호출합니다.
+//  3. 남은 결과가 필요 없으면 Drain을 호출합니다.
+//  4. 클라이언트를 더 이상 사용하지 않으면 Close를 호출합니다.
+func (c *gRPCClient) Drain(ctx context.Context, stream pb.OnlineDecoder_DecodeClient) error {
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				done <- err
+				return
+			}
+		}
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SendPCM은 r에서 16-bit PCM 음성을 frameSize 바이트씩 읽어 stream으로 전송합니다.
+// r이 io.EOF를 반환하면 남은 음성을 전송한 뒤 nil을 반환하며, stream의 context가 취소되면 해당 에러를 반환합니다.
+// 전송을 마친 뒤 CloseSend 또는 Drain은 호출하는 쪽에서 호출해야 합니다.
+//
+// frameSize는 짝수여야 합니다. 100ms 단위의 전송을 권장하며, 16-bit 모노 음성 기준으로
+// 8kHz는 1600 바이트, 16kHz는 3200 바이트, 44.1kHz는 8820 바이트, 48kHz는 9600 바이트 입니다.
+func (c *gRPCClient) SendPCM(stream pb.OnlineDecoder_DecodeClient, r io.Reader, frameSize int) error {
+	if frameSize <= 0 || frameSize%2 != 0 {
+		return fmt.Errorf("frame size must be a positive even number of bytes, got %d", frameSize)
+	}
+	buf := make([]byte, frameSize)
+	for {
+		if err := stream.Context().Err(); err != nil {
+			return err
+		}
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			// Send 이후에는 메시지를 수정하면 안 되므로 매번 새로운 슬라이스를 전달합니다.
+			chunk := append([]byte(nil), buf[:n]...)
+			if sendErr := stream.Send(&pb.DecoderRequest{
+				StreamingRequest: &pb.DecoderRequest_AudioContent{AudioContent: chunk},
+			}); sendErr != nil {
+				return sendErr
+			}
+		}
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
 func (c *gRPCClient) Close() error {
 	return c.coonPool.Close()
 }
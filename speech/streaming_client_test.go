@@ -0,0 +1,67 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/vito-ai/go-genproto/vito-openapi/stt"
+	"google.golang.org/grpc"
+)
+
+// fakeDecodeStream serves the responses queued in results, then blocks until closed is closed.
+type fakeDecodeStream struct {
+	grpc.ClientStream
+	results   chan *pb.DecoderResponse
+	closed    chan struct{}
+	closeSent bool
+}
+
+func (s *fakeDecodeStream) Send(*pb.DecoderRequest) error { return nil }
+
+func (s *fakeDecodeStream) CloseSend() error {
+	s.closeSent = true
+	return nil
+}
+
+func (s *fakeDecodeStream) Recv() (*pb.DecoderResponse, error) {
+	select {
+	case res := <-s.results:
+		return res, nil
+	default:
+	}
+	<-s.closed
+	return nil, io.EOF
+}
+
+func newFakeDecodeStream(n int) *fakeDecodeStream {
+	s := &fakeDecodeStream{results: make(chan *pb.DecoderResponse, n), closed: make(chan struct{})}
+	for range n {
+		s.results <- &pb.DecoderResponse{}
+	}
+	return s
+}
+
+func TestDrain(t *testing.T) {
+	c := &gRPCClient{}
+
+	stream := newFakeDecodeStream(3)
+	close(stream.closed)
+	if err := c.Drain(context.Background(), stream); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if !stream.closeSent || len(stream.results) != 0 {
+		t.Fatalf("Drain() did not close sending and read every result")
+	}
+
+	// the server never ends the stream
+	stuck := newFakeDecodeStream(0)
+	defer close(stuck.closed)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.Drain(ctx, stuck); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Drain() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
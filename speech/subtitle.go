@@ -0,0 +1,225 @@
+package speech
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrNoTimings is returned when subtitles are requested for a result whose utterances have no timing.
+var ErrNoTimings = errors.New("result has no utterance timings")
+
+// SubtitleOptions controls the cues written by WriteSRT and WriteVTT.
+type SubtitleOptions struct {
+	// TimeMargin extends every cue by this many milliseconds before its start and after its end,
+	// for readability. Padding never makes a cue start before zero or overlap its neighbors: between
+	// two close cues the gap is split at its middle. Defaults to zero.
+	TimeMargin int
+	// Overlap selects how utterances which overlap in time, as diarized cross-talk does, are written.
+	// Defaults to OverlapClamp.
+	Overlap OverlapMode
+}
+
+// OverlapMode is the handling of overlapping utterances in subtitles.
+// Cues are written in the order of their start times, whatever the mode.
+type OverlapMode int
+
+const (
+	// OverlapClamp ends the earlier cue where the later one starts; the later cue keeps its times.
+	// When the two start at the same time, nothing would be left of the earlier cue, so they are
+	// merged as with OverlapMerge, which is the only case where speaker labels are added.
+	OverlapClamp OverlapMode = iota
+	// OverlapReject fails with ErrOverlappingCues.
+	OverlapReject
+	// OverlapMerge combines overlapping utterances into one cue spanning all of them, with one line
+	// per utterance. When the utterances have different speakers, every line is prefixed with the
+	// speaker label, numbered from 1 as in TranscriptWithSpeakers ("Speaker 1: ..." for Spk 0);
+	// the cues of a single speaker are not labeled.
+	OverlapMerge
+)
+
+// ErrOverlappingCues is returned by WriteSRT and WriteVTT with OverlapReject when utterances overlap.
+var ErrOverlappingCues = errors.New("utterances overlap")
+
+// cue is a subtitle cue, with times in milliseconds.
+type cue struct {
+	start, end int
+	text       string
+}
+
+// cues returns the cues of the utterances, with overlaps handled by opts.Overlap, padded by opts.TimeMargin.
+func (r *RecognizeResponse) cues(opts SubtitleOptions) ([]cue, error) {
+	if r.Results == nil {
+		return nil, nil
+	}
+	utterances := slices.Clone(r.Results.Utterances)
+	slices.SortStableFunc(utterances, func(a, b *Utterance) int { return a.StartAt - b.StartAt })
+
+	var cues []cue
+	// utterances of the last cue, when they are merged
+	var group []*Utterance
+	for _, u := range utterances {
+		c := cue{start: u.StartAt, end: u.StartAt + u.Duration, text: u.Msg}
+		if len(cues) == 0 {
+			cues, group = append(cues, c), []*Utterance{u}
+			continue
+		}
+		last := &cues[len(cues)-1]
+		if c.start >= last.end {
+			cues, group = append(cues, c), []*Utterance{u}
+			continue
+		}
+		switch {
+		case opts.Overlap == OverlapReject:
+			return nil, fmt.Errorf("%w: %q at %s and %q at %s", ErrOverlappingCues,
+				last.text, formatCueTime(last.start, '.'), c.text, formatCueTime(c.start, '.'))
+		case opts.Overlap == OverlapClamp && c.start > last.start:
+			last.end = c.start
+			cues, group = append(cues, c), []*Utterance{u}
+		default:
+			group = append(group, u)
+			*last = mergeCue(group)
+		}
+	}
+	if opts.TimeMargin <= 0 {
+		return cues, nil
+	}
+
+	padded := make([]cue, len(cues))
+	for i, c := range cues {
+		c.start = max(c.start-opts.TimeMargin, 0)
+		c.end += opts.TimeMargin
+		// padding is clamped at the boundaries, but never moves the original times
+		if i > 0 {
+			c.start = max(c.start, min(boundary(cues[i-1], cues[i]), cues[i].start))
+		}
+		if i+1 < len(cues) {
+			c.end = min(c.end, max(boundary(cues[i], cues[i+1]), cues[i].end))
+		}
+		padded[i] = c
+	}
+	return padded, nil
+}
+
+// mergeCue returns the cue of overlapping utterances, labeled with their speakers when they differ.
+func mergeCue(utterances []*Utterance) cue {
+	c := cue{start: utterances[0].StartAt}
+	labeled := false
+	for _, u := range utterances {
+		c.end = max(c.end, u.StartAt+u.Duration)
+		labeled = labeled || u.Spk != utterances[0].Spk
+	}
+	lines := make([]string, len(utterances))
+	for i, u := range utterances {
+		if labeled {
+			lines[i] = fmt.Sprintf("Speaker %d: %s", u.Spk+1, u.Msg)
+		} else {
+			lines[i] = u.Msg
+		}
+	}
+	c.text = strings.Join(lines, "\n")
+	return c
+}
+
+// boundary returns the time padding of prev and next must not cross: the middle of the gap between
+// them, or the end of prev when they already overlap, which keeps both unpadded.
+func boundary(prev, next cue) int {
+	if prev.end >= next.start {
+		return prev.end
+	}
+	return prev.end + (next.start-prev.end)/2
+}
+
+// WriteSRT writes the utterances of r to w as SubRip (.srt) subtitles, one cue per utterance
+// unless overlapping utterances are merged.
+func (r *RecognizeResponse) WriteSRT(w io.Writer, opts SubtitleOptions) error {
+	cues, err := r.cues(opts)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for i, c := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1, formatCueTime(c.start, ','), formatCueTime(c.end, ','), c.text)
+	}
+	return bw.Flush()
+}
+
+// WriteVTT writes the utterances of r to w as WebVTT (.vtt) subtitles, one cue per utterance
+// unless overlapping utterances are merged.
+func (r *RecognizeResponse) WriteVTT(w io.Writer, opts SubtitleOptions) error {
+	cues, err := r.cues(opts)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for _, c := range cues {
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n", formatCueTime(c.start, '.'), formatCueTime(c.end, '.'), c.text)
+	}
+	return bw.Flush()
+}
+
+// formatCueTime formats ms as hh:mm:ss followed by sep and the milliseconds.
+func formatCueTime(ms int, sep byte) string {
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// TranscribeToSRTFile recognizes param like Recognize and writes the result as SubRip subtitles to
+// outPath. The file is written atomically: the subtitles go to a temporary file in the same directory,
+// which is renamed to outPath once complete, so outPath never holds partial subtitles. It fails with
+// ErrNoTimings, leaving outPath untouched, when an utterance has no duration.
+func (c *restClient) TranscribeToSRTFile(ctx context.Context, param *RecognizeRequest, outPath string) error {
+	if err := c.begin(); err != nil {
+		return err
+	}
+	defer c.inflight.Done()
+
+	res, err := c.recognize(ctx, param)
+	if err != nil {
+		return err
+	}
+	if res.Results != nil {
+		for i, u := range res.Results.Utterances {
+			if u.Duration <= 0 {
+				return fmt.Errorf("%w: utterance %d has no duration", ErrNoTimings, i)
+			}
+		}
+	}
+	return writeFileAtomic(outPath, func(w io.Writer) error {
+		return res.WriteSRT(w, SubtitleOptions{})
+	})
+}
+
+// writeFileAtomic writes path with write through a temporary file renamed over path.
+func writeFileAtomic(path string, write func(w io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err = write(f); err != nil {
+		return err
+	}
+	// CreateTemp creates the file readable by its owner only
+	if err = f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,21 @@
+package speech
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteSRTMergedSpeakerLabels(t *testing.T) {
+	r := &RecognizeResponse{Results: &Results{Utterances: []*Utterance{
+		{Spk: 0, StartAt: 0, Duration: 2000, Msg: "안녕하세요"},
+		{Spk: 1, StartAt: 1000, Duration: 2000, Msg: "네"},
+	}}}
+	var b strings.Builder
+	if err := r.WriteSRT(&b, SubtitleOptions{Overlap: OverlapMerge}); err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:00,000 --> 00:00:03,000\nSpeaker 1: 안녕하세요\nSpeaker 2: 네\n\n"
+	if b.String() != want {
+		t.Fatalf("WriteSRT() = %q, want %q", b.String(), want)
+	}
+}
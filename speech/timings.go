@@ -0,0 +1,33 @@
+package speech
+
+import (
+	"context"
+	"time"
+)
+
+// RequestTimings is a breakdown of the time spent by a Recognize call, collected when
+// RecognizeRequest.Timings is set. When a failed job is submitted again (ClientOption.FailedRetries),
+// the durations and counts add up over all the attempts, and for RecognizeLarge over all the parts.
+type RequestTimings struct {
+	// Auth is the time spent obtaining an access token, close to zero when a cached one is used.
+	Auth time.Duration
+	// Upload is the time spent submitting the audio, until the server returned the job id.
+	Upload time.Duration
+	// Polls is the number of result polls, and Polling the time spent in them, excluding the
+	// delays between polls and the download of the completed result.
+	Polls   int
+	Polling time.Duration
+	// Total is the duration of the whole call.
+	Total time.Duration
+}
+
+type timingsKey struct{}
+
+func withTimings(ctx context.Context, t *RequestTimings) context.Context {
+	return context.WithValue(ctx, timingsKey{}, t)
+}
+
+func timingsFromContext(ctx context.Context) *RequestTimings {
+	t, _ := ctx.Value(timingsKey{}).(*RequestTimings)
+	return t
+}
@@ -0,0 +1,227 @@
+package speech
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// AbsoluteUtterance is an utterance placed on the wall clock.
+type AbsoluteUtterance struct {
+	*Utterance
+	Start time.Time
+	End   time.Time
+}
+
+// WithTimeBase maps the relative offsets of the utterances to absolute times,
+// treating start as the moment the audio began.
+// Offsets are reported by the server in milliseconds, so the result has millisecond precision.
+func (r *RecognizeResponse) WithTimeBase(start time.Time) []AbsoluteUtterance {
+	if r.Results == nil {
+		return nil
+	}
+	utterances := make([]AbsoluteUtterance, 0, len(r.Results.Utterances))
+	for _, u := range r.Results.Utterances {
+		begin := start.Add(time.Duration(u.StartAt) * time.Millisecond)
+		utterances = append(utterances, AbsoluteUtterance{
+			Utterance: u,
+			Start:     begin,
+			End:       begin.Add(time.Duration(u.Duration) * time.Millisecond),
+		})
+	}
+	return utterances
+}
+
+// IsEmpty reports whether the job produced no speech:
+// there are no utterances, or every utterance's text is whitespace.
+func (r *RecognizeResponse) IsEmpty() bool {
+	if r.Results == nil {
+		return true
+	}
+	for _, u := range r.Results.Utterances {
+		if strings.TrimSpace(u.Msg) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// FullTranscript returns the text of all utterances, one per line.
+func (r *RecognizeResponse) FullTranscript() string {
+	if r.Results == nil {
+		return ""
+	}
+	lines := make([]string, 0, len(r.Results.Utterances))
+	for _, u := range r.Results.Utterances {
+		lines = append(lines, u.Msg)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TranscriptWithSpeakers returns the transcript with one line per speaker turn,
+// prefixed with the speaker label ("Speaker 1: ..."). Labels are numbered from 1, so Spk 0 is "Speaker 1".
+// Consecutive utterances of the same speaker form a turn.
+// Without diarization every utterance belongs to speaker 0, and FullTranscript is returned instead.
+func (r *RecognizeResponse) TranscriptWithSpeakers() string {
+	if r.Results == nil {
+		return ""
+	}
+	if !r.hasSpeakers() {
+		return r.FullTranscript()
+	}
+
+	var lines []string
+	var turn []string
+	spk := r.Results.Utterances[0].Spk
+	flush := func() {
+		lines = append(lines, fmt.Sprintf("Speaker %d: %s", spk+1, strings.Join(turn, " ")))
+		turn = turn[:0]
+	}
+	for _, u := range r.Results.Utterances {
+		if u.Spk != spk {
+			flush()
+			spk = u.Spk
+		}
+		turn = append(turn, u.Msg)
+	}
+	flush()
+	return strings.Join(lines, "\n")
+}
+
+// hasSpeakers reports whether the utterances carry diarization labels.
+func (r *RecognizeResponse) hasSpeakers() bool {
+	for _, u := range r.Results.Utterances {
+		if u.Spk != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Sentence is a sentence of an utterance with approximate timing, in milliseconds.
+type Sentence struct {
+	Text    string
+	StartAt int
+	EndAt   int
+	Spk     int
+}
+
+// Sentences splits the text of every utterance into sentences.
+//
+// Sentences end at '.', '?', '!', '…' or '。' followed by whitespace or the end of the utterance;
+// an utterance without such punctuation is a single sentence. The utterance's time span is
+// divided among its sentences in proportion to their length in characters, so the timing is
+// only an approximation which ignores pauses and speaking rate.
+func (r *RecognizeResponse) Sentences() []Sentence {
+	if r.Results == nil {
+		return nil
+	}
+	var sentences []Sentence
+	for _, u := range r.Results.Utterances {
+		parts := splitSentences(u.Msg)
+		total := 0
+		for _, p := range parts {
+			total += utf8.RuneCountInString(p)
+		}
+
+		seen := 0
+		for _, p := range parts {
+			start := u.StartAt + u.Duration*seen/total
+			seen += utf8.RuneCountInString(p)
+			sentences = append(sentences, Sentence{
+				Text:    p,
+				StartAt: start,
+				EndAt:   u.StartAt + u.Duration*seen/total,
+				Spk:     u.Spk,
+			})
+		}
+	}
+	return sentences
+}
+
+// splitSentences splits text after sentence-ending punctuation followed by whitespace.
+func splitSentences(text string) []string {
+	var parts []string
+	runes := []rune(text)
+	begin := 0
+	for i, r := range runes {
+		if !strings.ContainsRune(".?!…。", r) {
+			continue
+		}
+		if i+1 < len(runes) && !isSpace(runes[i+1]) {
+			continue
+		}
+		if p := strings.TrimSpace(string(runes[begin : i+1])); p != "" {
+			parts = append(parts, p)
+		}
+		begin = i + 1
+	}
+	if p := strings.TrimSpace(string(runes[begin:])); p != "" {
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// Segment is a stable, typed view of an utterance, independent of the JSON shape of the result.
+// Times are in milliseconds.
+type Segment struct {
+	Text     string
+	StartAt  int
+	Duration int
+	Speaker  int
+	// Confidence is the confidence of the best alternative, or zero when the server did not
+	// report one (alternatives are only returned when RecognitionConfig.MaxAlternatives is set).
+	Confidence float64
+}
+
+// Utterances returns the utterances of the result as Segments.
+func (r *RecognizeResponse) Utterances() []Segment {
+	if r.Results == nil {
+		return nil
+	}
+	segments := make([]Segment, len(r.Results.Utterances))
+	for i, u := range r.Results.Utterances {
+		segments[i] = Segment{
+			Text:     u.Msg,
+			StartAt:  u.StartAt,
+			Duration: u.Duration,
+			Speaker:  u.Spk,
+		}
+		if len(u.Alternatives) > 0 {
+			segments[i].Confidence = u.Alternatives[0].Confidence
+		}
+	}
+	return segments
+}
+
+// FrameRange is the span of an utterance in video frames: frames Start through End-1 show it.
+type FrameRange struct {
+	Start, End int
+}
+
+// FramesAt converts the utterances to frame ranges at fps frames per second, one per utterance.
+//
+// The start is rounded down to the frame being shown when the utterance starts, and the end is
+// rounded up, so that the range covers the whole utterance: a time falling exactly on a frame
+// boundary belongs to the frame which begins there. Every range spans at least one frame.
+// It returns nil when fps is not positive.
+func (r *RecognizeResponse) FramesAt(fps float64) []FrameRange {
+	if r.Results == nil || fps <= 0 {
+		return nil
+	}
+	// absorbs floating point error, e.g. 1001ms at 30000/1001fps is exactly frame 30
+	const epsilon = 1e-6
+	frames := make([]FrameRange, len(r.Results.Utterances))
+	for i, u := range r.Results.Utterances {
+		start := int(math.Floor(float64(u.StartAt)*fps/1000 + epsilon))
+		end := int(math.Ceil(float64(u.StartAt+u.Duration)*fps/1000 - epsilon))
+		frames[i] = FrameRange{Start: start, End: max(end, start+1)}
+	}
+	return frames
+}
@@ -0,0 +1,15 @@
+package speech
+
+import "testing"
+
+func TestTranscriptWithSpeakers(t *testing.T) {
+	r := &RecognizeResponse{Results: &Results{Utterances: []*Utterance{
+		{Spk: 0, Msg: "안녕하세요"},
+		{Spk: 0, Msg: "반갑습니다"},
+		{Spk: 1, Msg: "네"},
+	}}}
+	want := "Speaker 1: 안녕하세요 반갑습니다\nSpeaker 2: 네"
+	if got := r.TranscriptWithSpeakers(); got != want {
+		t.Fatalf("TranscriptWithSpeakers() = %q, want %q", got, want)
+	}
+}
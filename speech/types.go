@@ -1,16 +1,98 @@
 package speech
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 type RecognizeRequest struct {
 	// 음성파일 처리를 위한 Config를 정의합니다.
 	// Config를 작성하지 않으면 Default 값으로 사용됩니다.
 	Config RecognitionConfig
-	// Content 와 FilePath 둘 중 하나만을 전달해야합니다.
-	// 만약 두 개 동시에 제공한다면 에러가 발생합니다.
+	// Content, FilePath, Reader, Open 중 하나만을 전달해야합니다.
+	// 만약 두 개 이상 동시에 제공한다면 에러가 발생합니다.
 	AudioSource RecognitionAudio
+	// 전사 요청 URL에 추가할 query parameter 입니다.
+	// ClientOption.QueryParams와 같은 key가 있으면 두 값이 모두 전달됩니다.
+	QueryParams url.Values
+	// true이면 Recognize가 결과를 기다리지 않고 전사 요청 직후 반환합니다.
+	// 이때 반환되는 RecognizeResponse에는 Id만 채워져 있습니다.
+	Async bool
+	// 0보다 크면 요청 전체(업로드와 결과 대기)에 적용되는 제한 시간입니다.
+	// 전달한 context에 이미 더 이른 deadline이 있으면 그 deadline이 적용됩니다.
+	Timeout time.Duration
+	// nil이 아니면 Recognize, RecognizeRaw, Transcribe, RecognizeLarge가 인증, 업로드, 결과 조회에 걸린 시간을 기록합니다.
+	// 기록은 호출이 실패해도 남으며, 이전 값은 지워집니다. RecognizeLarge는 나눈 부분마다 따로 기록한 뒤
+	// 합산합니다. 동시에 실행되는 호출끼리 공유하면 안 됩니다.
+	Timings *RequestTimings
+}
+
+// validate는 음성, Config, QueryParams의 문제를 모두 모아 *ValidationError로 반환합니다.
+func (r *RecognizeRequest) validate() error {
+	var mismatch error
+	if f, e := r.AudioSource.Format, r.Config.Encoding; f != "" && e != "" && f != e {
+		mismatch = fmt.Errorf("%w: encoding %q does not match the audio format %q", ErrInvalidConfig, e, f)
+	}
+	return newValidationError(r.AudioSource.validate(), r.Config.validate(), mismatch, validateQueryParams(r.QueryParams))
+}
+
+// config는 Encoding이 설정되지 않은 경우 음성의 Format을 반영한 Config를 반환합니다.
+func (r *RecognizeRequest) config() RecognitionConfig {
+	rc := r.Config
+	if rc.Encoding == "" {
+		rc.Encoding = r.AudioSource.Format
+	}
+	return rc
+}
+
+// withTimeout은 Timeout이 설정된 경우 제한 시간이 적용된 context를 반환합니다.
+func (r *RecognizeRequest) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.Timeout > 0 {
+		return context.WithTimeout(ctx, r.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// validateQueryParams는 key가 URL에서 그대로 사용할 수 있는 문자로만 이루어져 있고
+// value에 제어 문자가 없는지 확인합니다.
+func validateQueryParams(params url.Values) error {
+	var errs []error
+	for key, values := range params {
+		if key == "" {
+			errs = append(errs, fmt.Errorf("%w: query parameter key must not be empty", ErrInvalidQueryParams))
+			continue
+		}
+		for _, r := range key {
+			if !isUnreservedRune(r) {
+				errs = append(errs, fmt.Errorf("%w: query parameter key %q contains invalid character %q", ErrInvalidQueryParams, key, r))
+				break
+			}
+		}
+		for _, v := range values {
+			if i := strings.IndexFunc(v, func(r rune) bool { return r < 0x20 || r == 0x7f }); i >= 0 {
+				errs = append(errs, fmt.Errorf("%w: query parameter %q has a value with control character %q", ErrInvalidQueryParams, key, v[i]))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isUnreservedRune(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' ||
+		r == '-' || r == '.' || r == '_' || r == '~'
 }
 
 type ResultId string
@@ -42,6 +124,222 @@ type RecognitionConfig struct {
 	// 특정 키워드에 대한 전사 정확도를 높이기 위해 사용됩니다.
 	// 키워드는 한글만 지원합니다.
 	Keywords []string `json:"keywords,omitempty"`
+	// 대소문자, 문장부호, 숫자 표기 방식 등 출력 형식을 세부적으로 정의합니다.
+	// 설정하지 않은 항목은 서버의 Default 값이 사용됩니다.
+	Formatting *FormattingOptions `json:"formatting,omitempty"`
+	// 전사 스타일을 정의합니다. 간투어 필터 설정(UseDisfluencyFilter)으로 변환되어 전달되며,
+	// UseDisfluencyFilter를 직접 설정한 경우 해당 값이 우선합니다.
+	// 설정하지 않으면 서버의 Default 값이 사용됩니다.
+	TranscriptStyle TranscriptStyle `json:"-"`
+	// 발화마다 반환받을 후보 전사 결과의 최대 개수를 정의합니다. 최대 10개까지 설정할 수 있습니다.
+	// 설정하지 않으면 최상위 결과만 반환됩니다.
+	MaxAlternatives int `json:"max_alternatives,omitempty"`
+	// 스테레오 음성의 각 채널을 독립적으로 전사할지 정의합니다. Default 값은 False입니다.
+	// 결과의 각 발화에는 Channel이 표시되며, 모노 WAV 파일에는 사용할 수 없습니다.
+	SeparateChannels bool `json:"separate_channels,omitempty"`
+	// 사용 환경에 맞춰 조정된 설정 묶음을 정의합니다. 직접 설정한 값이 Preset의 값보다 우선합니다.
+	Preset Preset `json:"-"`
+	// 음성의 인코딩을 정의합니다. 설정하지 않으면 RecognitionAudio.Format이 사용되며,
+	// 둘 다 설정하지 않으면 서버가 음성으로부터 추정합니다.
+	Encoding AudioFormat `json:"encoding,omitempty"`
+	// 음성의 sample rate(Hz)와 채널 수를 정의합니다. 헤더 없는 PCM처럼 음성으로부터 알 수 없는 경우에 설정합니다.
+	// WAV 파일에 설정하면 업로드 전에 WAV 헤더의 값과 일치하는지 확인하여, 다르면 에러를 반환합니다.
+	SampleRate int `json:"sample_rate,omitempty"`
+	Channels   int `json:"channels,omitempty"`
+}
+
+// AudioFormat은 음성의 형식입니다. 헤더 없는 PCM처럼 서버가 형식을 추정할 수 없는 음성에 지정합니다.
+type AudioFormat string
+
+const (
+	AudioFormatWAV     AudioFormat = "WAV"
+	AudioFormatFLAC    AudioFormat = "FLAC"
+	AudioFormatMP3     AudioFormat = "MP3"
+	AudioFormatOggOpus AudioFormat = "OGG_OPUS"
+	// 헤더 없는 16-bit little-endian PCM
+	AudioFormatLinear16 AudioFormat = "LINEAR16"
+	// 헤더 없는 8-bit G.711 μ-law, A-law
+	AudioFormatMulaw AudioFormat = "MULAW"
+	AudioFormatAlaw  AudioFormat = "ALAW"
+)
+
+// audioFormats는 형식별로 업로드할 파일의 확장자와 Content-Type입니다.
+var audioFormats = map[AudioFormat]struct{ ext, contentType string }{
+	AudioFormatWAV:      {".wav", "audio/wav"},
+	AudioFormatFLAC:     {".flac", "audio/flac"},
+	AudioFormatMP3:      {".mp3", "audio/mpeg"},
+	AudioFormatOggOpus:  {".ogg", "audio/ogg"},
+	AudioFormatLinear16: {".pcm", "audio/L16"},
+	AudioFormatMulaw:    {".ulaw", "audio/basic"},
+	AudioFormatAlaw:     {".alaw", "audio/x-alaw-basic"},
+}
+
+func (f AudioFormat) validate() error {
+	if _, ok := audioFormats[f]; f != "" && !ok {
+		return fmt.Errorf("unknown audio format %q", f)
+	}
+	return nil
+}
+
+// Preset은 사용 환경에 맞춰 조정된 RecognitionConfig의 기본값 묶음입니다.
+type Preset string
+
+const (
+	// 전화 통화: Domain CALL, 화자 분리(2명), 간투어 필터 사용
+	PresetPhone Preset = "phone"
+	// 회의: Domain GENERAL, 화자 분리, 문단 나누기 사용
+	PresetMeeting Preset = "meeting"
+	// 방송: Domain GENERAL, 문단 나누기, 간투어 필터 사용
+	PresetBroadcast Preset = "broadcast"
+)
+
+// apply는 rc에서 설정되지 않은 값을 Preset의 값으로 채웁니다.
+func (p Preset) apply(rc *RecognitionConfig) {
+	var domain string
+	var diarization, paragraph, disfluency *bool
+	var spkCount int
+	switch p {
+	case PresetPhone:
+		domain, diarization, disfluency, spkCount = "CALL", boolPtr(true), boolPtr(true), 2
+	case PresetMeeting:
+		domain, diarization, paragraph = "GENERAL", boolPtr(true), boolPtr(true)
+	case PresetBroadcast:
+		domain, paragraph, disfluency = "GENERAL", boolPtr(true), boolPtr(true)
+	default:
+		return
+	}
+
+	if rc.Domain == "" {
+		rc.Domain = domain
+	}
+	if rc.UseDiarization == nil {
+		rc.UseDiarization = diarization
+	}
+	if rc.Diarization == nil && spkCount > 0 && rc.UseDiarization != nil && *rc.UseDiarization {
+		rc.Diarization = &DiarizationConfig{SpkCount: spkCount}
+	}
+	if rc.UseParagraphSplitter == nil {
+		rc.UseParagraphSplitter = paragraph
+	}
+	if rc.UseDisfluencyFilter == nil {
+		rc.UseDisfluencyFilter = disfluency
+	}
+}
+
+const maxAlternativesLimit = 10
+
+// TranscriptStyle은 간투어("음", "어"), 반복 등을 결과에 포함할지를 정의합니다.
+type TranscriptStyle string
+
+const (
+	// 간투어와 반복을 그대로 전사합니다.
+	TranscriptStyleVerbatim TranscriptStyle = "verbatim"
+	// 간투어와 반복을 제거하고 전사합니다.
+	TranscriptStyleClean TranscriptStyle = "clean"
+)
+
+// submitted는 서버로 전송되는 Config의 사본을 반환합니다.
+func (rc RecognitionConfig) submitted() *RecognitionConfig {
+	resolved := rc.resolve()
+	return &resolved
+}
+
+// resolve는 서버로 전달할 최종 Config를 반환합니다.
+func (rc RecognitionConfig) resolve() RecognitionConfig {
+	if rc.UseDisfluencyFilter == nil {
+		switch rc.TranscriptStyle {
+		case TranscriptStyleVerbatim:
+			rc.UseDisfluencyFilter = boolPtr(false)
+		case TranscriptStyleClean:
+			rc.UseDisfluencyFilter = boolPtr(true)
+		}
+	}
+	rc.Preset.apply(&rc)
+	return rc
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func (rc *RecognitionConfig) validate() error {
+	var errs []error
+	switch rc.TranscriptStyle {
+	case "", TranscriptStyleVerbatim, TranscriptStyleClean:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unknown transcript style %q", ErrInvalidConfig, rc.TranscriptStyle))
+	}
+	switch rc.Preset {
+	case "", PresetPhone, PresetMeeting, PresetBroadcast:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unknown preset %q", ErrInvalidConfig, rc.Preset))
+	}
+	if rc.MaxAlternatives < 0 || rc.MaxAlternatives > maxAlternativesLimit {
+		errs = append(errs, fmt.Errorf("%w: max alternatives must be between 0 and %d, got %d", ErrInvalidConfig, maxAlternativesLimit, rc.MaxAlternatives))
+	}
+	if rc.Formatting != nil {
+		errs = append(errs, rc.Formatting.validate())
+	}
+	if rc.SampleRate < 0 || rc.Channels < 0 {
+		errs = append(errs, fmt.Errorf("%w: sample rate and channels must not be negative", ErrInvalidConfig))
+	}
+	if err := rc.Encoding.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidConfig, err))
+	}
+	return errors.Join(errs...)
+}
+
+// Casing은 전사 결과의 대소문자 표기 방식입니다.
+type Casing string
+
+const (
+	CasingLower    Casing = "lower"
+	CasingUpper    Casing = "upper"
+	CasingSentence Casing = "sentence"
+)
+
+// Punctuation은 전사 결과의 문장부호 표기 수준입니다.
+type Punctuation string
+
+const (
+	PunctuationNone  Punctuation = "none"
+	PunctuationBasic Punctuation = "basic"
+	PunctuationFull  Punctuation = "full"
+)
+
+// ITNStyle은 숫자, 날짜, 통화 등의 표기 방식입니다.
+type ITNStyle string
+
+const (
+	ITNSpoken  ITNStyle = "spoken"
+	ITNWritten ITNStyle = "written"
+)
+
+// FormattingOptions는 전사 결과의 출력 형식 설정을 포함하는 구조체입니다.
+type FormattingOptions struct {
+	Casing      Casing      `json:"casing,omitempty"`
+	Punctuation Punctuation `json:"punctuation,omitempty"`
+	ITN         ITNStyle    `json:"itn,omitempty"`
+}
+
+func (fo *FormattingOptions) validate() error {
+	var errs []error
+	switch fo.Casing {
+	case "", CasingLower, CasingUpper, CasingSentence:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unknown casing %q", ErrInvalidConfig, fo.Casing))
+	}
+	switch fo.Punctuation {
+	case "", PunctuationNone, PunctuationBasic, PunctuationFull:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unknown punctuation %q", ErrInvalidConfig, fo.Punctuation))
+	}
+	switch fo.ITN {
+	case "", ITNSpoken, ITNWritten:
+	default:
+		errs = append(errs, fmt.Errorf("%w: unknown itn style %q", ErrInvalidConfig, fo.ITN))
+	}
+	return errors.Join(errs...)
 }
 
 // DiarizationConfig는 발화자 분리 설정을 포함하는 구조체입니다.
@@ -54,33 +352,387 @@ type ParagraphSplitterConfig struct {
 	Max int `json:"max"`
 }
 
-// Content 와 FilePath 둘 중 하나만을 전달해야합니다.
-// 만약 두 개 동시에 제공한다면 에러가 발생합니다.
+// Content, FilePath, Reader, Open 중 하나만을 전달해야합니다.
+// 만약 두 개 이상 동시에 제공한다면 에러가 발생합니다.
+//
+// 재시도 시 업로드 본문을 다시 만들 수 있도록, SDK는 시도마다 새로운 reader로 음성을 읽습니다.
+// Content, FilePath, Open은 항상 다시 읽을 수 있으며, Reader는 io.Seeker를 구현한 경우에만
+// 처음 위치로 되돌려 다시 읽습니다. Seek할 수 없는 Reader는 재시도가 비활성화됩니다.
+// Reader를 사용하는 요청은 Reader의 읽는 위치를 공유하므로, 여러 호출에서 동시에 사용하면 안 됩니다.
 type RecognitionAudio struct {
 	Content  []byte
 	FilePath string
+	// Reader의 내용을 임시 파일 없이 그대로 업로드합니다. (예: 수신한 요청의 multipart.File)
+	Reader io.Reader
+	// 시도마다 음성을 처음부터 읽는 새로운 reader를 반환합니다. 반환된 reader는 SDK가 닫습니다.
+	Open func() (io.ReadCloser, error)
+	// 업로드할 파일의 이름입니다. Content, Reader, Open에만 적용되며,
+	// FilePath를 사용하면 해당 파일의 이름이 사용됩니다.
+	FileName string
+	// 음성의 형식입니다. 설정하면 업로드할 파일의 Content-Type과 Config.Encoding에 반영되고,
+	// FileName이 없으면 형식에 맞는 확장자의 파일 이름이 사용되어 서버가 형식을 추정하지 않습니다.
+	Format AudioFormat
+
+	// Open과 같지만 요청의 context로 음성을 엽니다. AudioFromURL이 설정합니다.
+	openContext func(ctx context.Context) (io.ReadCloser, error)
+	// Reader를 처음 읽기 시작한 위치
+	readerOpened bool
+	readerOffset int64
 }
 
+// ErrAudioNotReplayable은 Seek할 수 없는 Reader의 음성을 다시 읽어야 할 때 반환됩니다.
+var ErrAudioNotReplayable = errors.New("audio reader cannot be read again; use a seekable Reader, Open, Content or FilePath")
+
 func (ra *RecognitionAudio) validate() error {
-	if ra.Content != nil && ra.FilePath != "" {
-		return fmt.Errorf("both Content and FilePath are provided; please provide only one")
+	count := 0
+	for _, set := range []bool{ra.Content != nil, ra.FilePath != "", ra.Reader != nil, ra.Open != nil} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		return fmt.Errorf("%w: more than one of Content, FilePath, Reader and Open are provided; please provide only one", ErrInvalidAudioSource)
 	}
-	if ra.Content == nil && ra.FilePath == "" {
-		return fmt.Errorf("neither Content nor FilePath is provided; please provide one")
+	if count == 0 {
+		return fmt.Errorf("%w: none of Content, FilePath, Reader and Open is provided; please provide one", ErrInvalidAudioSource)
 	}
+	if err := ra.Format.validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAudioSource, err)
+	}
+	return nil
+}
+
+// replayable은 open을 여러 번 호출할 수 있는지 반환합니다.
+func (ra *RecognitionAudio) replayable() bool {
+	if ra.Reader == nil {
+		return true
+	}
+	_, ok := ra.Reader.(io.Seeker)
+	return ok
+}
+
+// start는 open이 시도마다 같은 위치부터 읽을 수 있도록 Reader를 읽기 시작하는 위치를 기록하며,
+// Seek할 수 없는 Reader를 이미 읽었다면 ErrAudioNotReplayable을 반환합니다.
+// open이 호출자의 요청을 수정하지 않도록, 전사 요청마다 음성을 읽는 goroutine이 시작되기 전에
+// 한 번 호출됩니다.
+func (ra *RecognitionAudio) start() error {
+	if ra.Reader == nil {
+		return nil
+	}
+	rs, seekable := ra.Reader.(io.Seeker)
+	switch {
+	case ra.readerOpened && !seekable:
+		return ErrAudioNotReplayable
+	case ra.readerOpened:
+		return nil
+	case seekable:
+		offset, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		ra.readerOffset = offset
+	}
+	ra.readerOpened = true
 	return nil
 }
 
+// open은 음성을 처음부터 읽는 reader를 반환하며, 가능한 경우 ctx에 묶인 reader를 반환합니다.
+// start를 먼저 호출해야 합니다.
+func (ra *RecognitionAudio) open(ctx context.Context) (io.ReadCloser, error) {
+	switch {
+	case ra.Content != nil:
+		return io.NopCloser(bytes.NewReader(ra.Content)), nil
+	case ra.FilePath != "":
+		return os.Open(ra.FilePath)
+	case ra.openContext != nil:
+		return ra.openContext(ctx)
+	case ra.Open != nil:
+		return ra.Open()
+	case ra.Reader != nil:
+		if rs, ok := ra.Reader.(io.Seeker); ok {
+			if _, err := rs.Seek(ra.readerOffset, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		// Reader는 호출자가 닫습니다
+		return io.NopCloser(ra.Reader), nil
+	default:
+		return nil, errors.New("no audio source")
+	}
+}
+
+// size는 음성을 읽지 않고 알 수 있는 경우 음성의 크기(byte)를 반환합니다.
+func (ra *RecognitionAudio) size() (int64, bool) {
+	switch {
+	case ra.Content != nil:
+		return int64(len(ra.Content)), true
+	case ra.FilePath != "":
+		fi, err := os.Stat(ra.FilePath)
+		if err != nil {
+			// 파일을 열 때 보고됩니다
+			return 0, false
+		}
+		return fi.Size(), true
+	}
+	return 0, false
+}
+
+// fileName은 multipart 파일 part의 이름을 반환합니다.
+func (ra *RecognitionAudio) fileName() string {
+	if ra.FilePath != "" {
+		return ra.FilePath
+	}
+	if ra.FileName != "" {
+		return ra.FileName
+	}
+	return "rtzr-default-audiofile" + audioFormats[ra.Format].ext
+}
+
+// contentType은 multipart 파일 part의 Content-Type을 반환합니다.
+func (ra *RecognitionAudio) contentType() string {
+	if f, ok := audioFormats[ra.Format]; ok {
+		return f.contentType
+	}
+	return "application/octet-stream"
+}
+
+// AudioFromFile은 path의 파일을 읽는 RecognitionAudio를 반환합니다.
+// 파일이 존재하지 않거나 디렉터리이면 에러를 반환합니다.
+func AudioFromFile(path string) (RecognitionAudio, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return RecognitionAudio{}, fmt.Errorf("%w: %w", ErrInvalidAudioSource, err)
+	}
+	if fi.IsDir() {
+		return RecognitionAudio{}, fmt.Errorf("%w: %s is a directory", ErrInvalidAudioSource, path)
+	}
+	return RecognitionAudio{FilePath: path}, nil
+}
+
+// AudioFromBytes는 메모리의 음성 b를 업로드하는 RecognitionAudio를 반환합니다. b가 비어 있으면 에러를 반환합니다.
+func AudioFromBytes(b []byte) (RecognitionAudio, error) {
+	if len(b) == 0 {
+		return RecognitionAudio{}, fmt.Errorf("%w: audio content is empty", ErrInvalidAudioSource)
+	}
+	return RecognitionAudio{Content: b}, nil
+}
+
+// AudioFromReader는 r의 내용을 업로드하는 RecognitionAudio를 반환합니다.
+// *os.File인 경우 파일 이름이 업로드할 파일의 이름으로 사용됩니다.
+func AudioFromReader(r io.Reader) (RecognitionAudio, error) {
+	if r == nil {
+		return RecognitionAudio{}, fmt.Errorf("%w: reader is nil", ErrInvalidAudioSource)
+	}
+	return newRecognitionAudio(r)
+}
+
+// AudioFromURL은 rawURL의 음성을 client로 내려받아 업로드하는 RecognitionAudio를 반환합니다.
+// client가 nil이면 http.DefaultClient를 사용합니다. 내려받는 즉시 업로드되므로 임시 파일을 사용하지 않으며,
+// 다운로드는 요청(Recognize 등)의 context가 취소되면 중단됩니다. rawURL은 http 또는 https URL이어야 합니다.
+//
+// 음성을 보관하지 않으므로 필요할 때마다 다시 내려받습니다. 업로드를 시도할 때마다 내려받으며,
+// DedupeSubmissions를 사용하면 중복 확인을 위해, WithPollCallback을 사용하거나 Config에 SampleRate,
+// Channels, SeparateChannels를 설정하면 WAV 헤더를 읽기 위해 한 번씩 더 내려받습니다.
+func AudioFromURL(rawURL string, client *http.Client) (RecognitionAudio, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return RecognitionAudio{}, fmt.Errorf("%w: %w", ErrInvalidAudioSource, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return RecognitionAudio{}, fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidAudioSource, rawURL)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	openContext := func(ctx context.Context) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("cannot download audio from %s: %s", u.Redacted(), resp.Status)
+		}
+		return resp.Body, nil
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = ""
+	}
+	return RecognitionAudio{
+		Open:        func() (io.ReadCloser, error) { return openContext(context.Background()) },
+		FileName:    name,
+		openContext: openContext,
+	}, nil
+}
+
+func newRecognitionAudio(source any) (RecognitionAudio, error) {
+	switch src := source.(type) {
+	case string:
+		return RecognitionAudio{FilePath: src}, nil
+	case []byte:
+		return RecognitionAudio{Content: src}, nil
+	case *os.File:
+		return RecognitionAudio{Reader: src, FileName: filepath.Base(src.Name())}, nil
+	case io.Reader:
+		return RecognitionAudio{Reader: src}, nil
+	default:
+		return RecognitionAudio{}, fmt.Errorf("unsupported audio source type %T; use string, []byte or io.Reader", source)
+	}
+}
+
+// Status는 전사 작업의 상태입니다.
+type Status string
+
+const (
+	StatusTranscribing Status = "transcribing"
+	StatusCompleted    Status = "completed"
+	StatusFailed       Status = "failed"
+)
+
 type RecognizeResponse struct {
 	Id      ResultId `json:"id"`
-	Status  string   `json:"status"`
+	Status  Status   `json:"status"`
 	Results *Results `json:"results"`
+	// 서버가 실제로 적용한 Config 입니다. 서버가 제공하지 않으면 nil 입니다.
+	Config *RecognitionConfig `json:"config,omitempty"`
+	// 결과를 받아온 요청에 서버가 부여한 id (X-Request-Id 헤더) 입니다. 문의 시 함께 전달해주세요.
+	ServerRequestID string `json:"-"`
+	// 작업이 실패한 경우 서버가 제공한 실패 사유입니다. 제공하지 않으면 nil 입니다.
+	Error *ResultError `json:"error,omitempty"`
+	// 전사에 사용된 엔진(모델)의 버전입니다. 서버가 제공하지 않으면 빈 문자열입니다.
+	EngineVersion string `json:"engine_version,omitempty"`
+	// 서버가 결과에 포함한 처리된 음성을 base64로 인코딩한 값입니다. ProcessedAudio로 디코딩합니다.
+	ProcessedAudioBase64 string `json:"processed_audio,omitempty"`
+	// 작업 제출 시 전송한 Config 입니다. Preset과 TranscriptStyle이 반영된 최종 값이며,
+	// 이 클라이언트로 제출한 작업의 응답에만 설정됩니다. (ReceiveResult 등으로 조회한 결과에는 nil)
+	RequestConfig *RecognitionConfig `json:"-"`
+	// 서버가 작업을 생성한 시각과 완료한 시각입니다. 결과의 created_at, completed_at 필드를
+	// RFC 3339 형식(예: 2024-01-02T15:04:05.123Z)으로 해석하며, 값이 없거나 형식이 다르면 0 값입니다.
+	CreatedAt   time.Time `json:"-"`
+	CompletedAt time.Time `json:"-"`
+}
+
+// responseTimes는 RecognizeResponse의 시각 필드의 JSON 표현입니다.
+type responseTimes struct {
+	CreatedAt   string `json:"created_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+}
+
+// recognizeResponseJSON은 UnmarshalJSON, MarshalJSON이 재귀하지 않도록 메서드가 없는 RecognizeResponse입니다.
+type recognizeResponseJSON RecognizeResponse
+
+func (r *RecognizeResponse) UnmarshalJSON(data []byte) error {
+	var v struct {
+		*recognizeResponseJSON
+		responseTimes
+	}
+	v.recognizeResponseJSON = (*recognizeResponseJSON)(r)
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.CreatedAt = parseResponseTime(v.responseTimes.CreatedAt)
+	r.CompletedAt = parseResponseTime(v.responseTimes.CompletedAt)
+	return nil
+}
+
+func (r RecognizeResponse) MarshalJSON() ([]byte, error) {
+	v := struct {
+		recognizeResponseJSON
+		responseTimes
+	}{recognizeResponseJSON: recognizeResponseJSON(r)}
+	if !r.CreatedAt.IsZero() {
+		v.responseTimes.CreatedAt = r.CreatedAt.Format(time.RFC3339Nano)
+	}
+	if !r.CompletedAt.IsZero() {
+		v.responseTimes.CompletedAt = r.CompletedAt.Format(time.RFC3339Nano)
+	}
+	return json.Marshal(v)
+}
+
+// parseResponseTime은 RFC 3339 형식의 s를 해석하며, 해석할 수 없으면 0 값을 반환합니다.
+func parseResponseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ErrNoProcessedAudio는 결과에 처리된 음성이 포함되지 않았을 때 반환됩니다.
+var ErrNoProcessedAudio = errors.New("result has no processed audio")
+
+// ProcessedAudio는 결과에 포함된 처리된 음성을 디코딩하여 반환합니다.
+// 음성이 없으면 ErrNoProcessedAudio를, base64 형식이 올바르지 않으면 해당 에러를 반환합니다.
+func (r *RecognizeResponse) ProcessedAudio() ([]byte, error) {
+	if r.ProcessedAudioBase64 == "" {
+		return nil, ErrNoProcessedAudio
+	}
+	audio, err := base64.StdEncoding.DecodeString(r.ProcessedAudioBase64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid processed audio encoding: %w", err)
+	}
+	return audio, nil
+}
+
+// ResultError는 실패한 전사 작업에 대해 서버가 제공한 실패 사유입니다.
+// errors.Is(err, ErrFailed)로 확인할 수 있습니다.
+type ResultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", ErrFailed, e.Message, e.Code)
+}
+
+func (e *ResultError) Is(target error) bool { return target == ErrFailed }
+
+// failure는 실패한 작업의 에러로, 실패 사유가 있으면 *ResultError, 없으면 ErrFailed를 반환합니다.
+func (r *RecognizeResponse) failure() error {
+	if r.Error != nil {
+		return r.Error
+	}
+	return ErrFailed
+}
+
+// TranscriptStyle은 서버가 적용한 전사 스타일을 반환합니다.
+// 서버가 Config를 제공하지 않았거나 간투어 필터 설정이 없으면 빈 문자열을 반환합니다.
+func (r *RecognizeResponse) TranscriptStyle() TranscriptStyle {
+	if r.Config == nil || r.Config.UseDisfluencyFilter == nil {
+		return ""
+	}
+	if *r.Config.UseDisfluencyFilter {
+		return TranscriptStyleClean
+	}
+	return TranscriptStyleVerbatim
 }
 
 type Results struct {
 	Utterances []*Utterance `json:"utterances"`
 	Verified   bool         `json:"verified"`
+	// 서버가 보고한 음성 품질 경고입니다. (클리핑, 낮은 볼륨, 샘플레이트 불일치 등)
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// Warning은 전사에 영향을 줄 수 있는 음성 품질 문제에 대한 경고입니다.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
+
+// Warnings는 서버가 보고한 음성 품질 경고를 반환합니다. 경고가 없으면 nil 입니다.
+func (r *RecognizeResponse) Warnings() []Warning {
+	if r.Results == nil {
+		return nil
+	}
+	return r.Results.Warnings
+}
+
 type Utterance struct {
 	Duration int              `json:"duration"`
 	Msg      string           `json:"msg"`
@@ -88,6 +740,45 @@ type Utterance struct {
 	SpkType  string           `json:"spk_type"`
 	StartAt  int              `json:"start_at"`
 	Words    []*TimeStampWord `json:"words"`
+	// 다국어 음성에서 감지된 발화의 언어입니다. 단일 언어 전사에서는 비어 있습니다.
+	Language string `json:"language,omitempty"`
+	// MaxAlternatives를 설정한 경우 신뢰도 순으로 정렬된 후보 전사 결과입니다.
+	Alternatives []*Alternative `json:"alternatives,omitempty"`
+	// SeparateChannels를 사용한 경우 발화가 속한 채널 번호입니다. (0부터 시작)
+	Channel int `json:"channel"`
+}
+
+// Alternative는 발화에 대한 후보 전사 결과입니다.
+type Alternative struct {
+	Msg        string  `json:"msg"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ITNApplied는 서버가 영어, 단어, 숫자 등의 표현 변환(use_itn)을 적용했는지 반환합니다.
+// 서버가 Config를 제공하지 않아 알 수 없으면 ok는 false 입니다.
+func (r *RecognizeResponse) ITNApplied() (applied bool, ok bool) {
+	if r.Config == nil || r.Config.UseItn == nil {
+		return false, false
+	}
+	return *r.Config.UseItn, true
+}
+
+// LanguagesUsed는 발화들에서 감지된 언어 목록을 처음 등장한 순서대로 반환합니다.
+// 단일 언어 전사처럼 언어 정보가 없으면 빈 목록을 반환합니다.
+func (r *RecognizeResponse) LanguagesUsed() []string {
+	var langs []string
+	if r.Results == nil {
+		return langs
+	}
+	seen := make(map[string]bool)
+	for _, u := range r.Results.Utterances {
+		if u.Language == "" || seen[u.Language] {
+			continue
+		}
+		seen[u.Language] = true
+		langs = append(langs, u.Language)
+	}
+	return langs
 }
 
 type TimeStampWord struct {
@@ -0,0 +1,146 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRecognitionConfigJSONKeys(t *testing.T) {
+	on := true
+	config := RecognitionConfig{
+		ModelName:            "sommers",
+		Language:             "ko",
+		UseDiarization:       &on,
+		Diarization:          &DiarizationConfig{SpkCount: 2},
+		UseItn:               &on,
+		UseDisfluencyFilter:  &on,
+		UseProfanityFilter:   &on,
+		UseParagraphSplitter: &on,
+		ParagraphSpliter:     &ParagraphSplitterConfig{Max: 50},
+		Domain:               "CALL",
+		UseWordTimestamp:     &on,
+		Keywords:             []string{"키워드"},
+		Formatting:           &FormattingOptions{Casing: CasingLower, Punctuation: PunctuationFull, ITN: ITNWritten},
+		TranscriptStyle:      TranscriptStyleClean,
+		MaxAlternatives:      3,
+		SeparateChannels:     true,
+		Preset:               PresetPhone,
+		Encoding:             AudioFormatLinear16,
+		SampleRate:           16000,
+		Channels:             1,
+	}
+	// Fail when a field is added without being populated here, so that its tag is checked too.
+	v := reflect.ValueOf(config)
+	for i := range v.NumField() {
+		if v.Field(i).IsZero() {
+			t.Fatalf("field %s is not populated", v.Type().Field(i).Name)
+		}
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"":                   {"channels", "diarization", "domain", "encoding", "formatting", "keywords", "language", "max_alternatives", "model_name", "paragraph_splitter", "sample_rate", "separate_channels", "use_diarization", "use_disfluency_filter", "use_itn", "use_paragraph_splitter", "use_profanity_filter", "use_word_timestamp"},
+		"diarization":        {"spk_count"},
+		"paragraph_splitter": {"max"},
+		"formatting":         {"casing", "itn", "punctuation"},
+	}
+	for path, keys := range want {
+		obj := got
+		if path != "" {
+			obj = got[path].(map[string]any)
+		}
+		var gotKeys []string
+		for k := range obj {
+			gotKeys = append(gotKeys, k)
+		}
+		slices.Sort(gotKeys)
+		if !slices.Equal(gotKeys, keys) {
+			t.Errorf("keys of %q = %v, want %v", path, gotKeys, keys)
+		}
+	}
+}
+
+func TestRecognitionAudioReaderReplay(t *testing.T) {
+	r := strings.NewReader("skipped audio")
+	r.Seek(int64(len("skipped ")), io.SeekStart)
+	ra := &RecognitionAudio{Reader: r}
+	if err := ra.start(); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		audio, err := ra.open(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(audio)
+		if string(b) != "audio" {
+			t.Fatalf("open() read %q, want %q", b, "audio")
+		}
+	}
+
+	once := &RecognitionAudio{Reader: io.MultiReader(strings.NewReader("audio"))}
+	if err := once.start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := once.start(); !errors.Is(err, ErrAudioNotReplayable) {
+		t.Fatalf("second start() error = %v, want %v", err, ErrAudioNotReplayable)
+	}
+}
+
+func TestAudioFromURL(t *testing.T) {
+	var used atomic.Bool
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		used.Store(true)
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stalled.wav" {
+			<-r.Context().Done()
+			return
+		}
+		io.WriteString(w, "audio")
+	}))
+	defer srv.Close()
+
+	ra, err := AudioFromURL(srv.URL+"/audio.wav", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, err := ra.open(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(audio)
+	audio.Close()
+	if string(b) != "audio" || !used.Load() || ra.FileName != "audio.wav" {
+		t.Fatalf("open() read %q, injected client used: %v, file name %q", b, used.Load(), ra.FileName)
+	}
+
+	stalled, err := AudioFromURL(srv.URL+"/stalled.wav", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := stalled.open(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("open() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,46 @@
+package speech
+
+import "context"
+
+// Upload is a submission in progress, started by StartUpload.
+//
+// The server only creates a job once it has received the whole upload, so there is no job to keep
+// for an upload which is aborted midway: a partially uploaded job does not exist and is never valid.
+type Upload struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	id  ResultId
+	err error
+}
+
+// StartUpload submits param like RecognizeAsync in the background and returns right away.
+// The upload is bound to ctx; use Wait for its outcome, or AbortUpload to stop it.
+func (c *restClient) StartUpload(ctx context.Context, param *RecognizeRequest) *Upload {
+	ctx, cancel := context.WithCancel(ctx)
+	u := &Upload{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(u.done)
+		defer cancel()
+		u.id, u.err = c.RecognizeAsync(ctx, param)
+	}()
+	return u
+}
+
+// Wait waits for the upload to finish and returns the id of the submitted job.
+func (u *Upload) Wait() (ResultId, error) {
+	<-u.done
+	return u.id, u.err
+}
+
+// AbortUpload cancels the upload if it is still in progress and waits for it to stop.
+// It reports whether a job id was obtained: if the upload had already finished, the job is kept
+// and its id is returned with true. Otherwise no job was created and it returns false.
+//
+// An upload aborted after the server accepted it but before its response was read reports false,
+// although the server may have created the job, which then runs unattended.
+func (u *Upload) AbortUpload() (ResultId, bool) {
+	u.cancel()
+	<-u.done
+	return u.id, u.err == nil
+}
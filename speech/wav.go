@@ -0,0 +1,194 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+var errNotWav = errors.New("audio is not a RIFF/WAVE file")
+
+// wavFormat holds the fields of a WAV header the SDK cares about.
+type wavFormat struct {
+	AudioFormat   uint16
+	NumChannels   uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+	// offset of the first sample and length of the "data" chunk
+	DataOffset int64
+	DataSize   int64
+}
+
+// readWavFormat parses the RIFF header of r up to the "data" chunk.
+// It returns errNotWav when r does not start with a RIFF/WAVE header.
+func readWavFormat(r io.Reader) (*wavFormat, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errNotWav
+		}
+		return nil, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, errNotWav
+	}
+
+	wf := &wavFormat{}
+	hasFmt := false
+	offset := int64(len(riff))
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return nil, fmt.Errorf("invalid wav header: %w", err)
+		}
+		offset += int64(len(hdr))
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("invalid wav header: fmt chunk too short")
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return nil, fmt.Errorf("invalid wav header: %w", err)
+			}
+			wf.AudioFormat = binary.LittleEndian.Uint16(chunk[0:2])
+			wf.NumChannels = binary.LittleEndian.Uint16(chunk[2:4])
+			wf.SampleRate = binary.LittleEndian.Uint32(chunk[4:8])
+			wf.ByteRate = binary.LittleEndian.Uint32(chunk[8:12])
+			wf.BlockAlign = binary.LittleEndian.Uint16(chunk[12:14])
+			wf.BitsPerSample = binary.LittleEndian.Uint16(chunk[14:16])
+			hasFmt = true
+		case "data":
+			if !hasFmt {
+				return nil, fmt.Errorf("invalid wav header: data chunk before fmt chunk")
+			}
+			wf.DataOffset = offset
+			wf.DataSize = size
+			return wf, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, fmt.Errorf("invalid wav header: %w", err)
+			}
+		}
+		// chunks are padded to an even size
+		if size%2 == 1 && id != "data" {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+				return nil, fmt.Errorf("invalid wav header: %w", err)
+			}
+			size++
+		}
+		offset += size
+	}
+}
+
+// wavFormat returns the WAV header of the audio, or errNotWav when it is not a WAV file.
+func (ra *RecognitionAudio) wavFormat(ctx context.Context) (*wavFormat, error) {
+	switch {
+	case ra.FilePath != "":
+		f, err := os.Open(ra.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return readWavFormat(f)
+	case ra.Content != nil:
+		return readWavFormat(bytes.NewReader(ra.Content))
+	case ra.Open != nil:
+		rc, err := ra.open(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return readWavFormat(rc)
+	case ra.Reader != nil:
+		// only seekable readers can be inspected without consuming them
+		rs, ok := ra.Reader.(io.ReadSeeker)
+		if !ok {
+			return nil, errNotWav
+		}
+		pos, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		wf, err := readWavFormat(rs)
+		if _, seekErr := rs.Seek(pos, io.SeekStart); seekErr != nil {
+			return nil, seekErr
+		}
+		return wf, err
+	default:
+		return nil, errNotWav
+	}
+}
+
+// checkWavFormat checks the config of r against the header of WAV audio: the sample rate, the
+// number of channels, and that separate channels are only requested for multi-channel audio.
+// Audio which is not WAV, or whose Format is another one, is not checked.
+func (r *RecognizeRequest) checkWavFormat(ctx context.Context) error {
+	rc := r.Config
+	if !rc.SeparateChannels && rc.SampleRate == 0 && rc.Channels == 0 {
+		return nil
+	}
+	if f := r.AudioSource.Format; f != "" && f != AudioFormatWAV {
+		return nil
+	}
+	wf, err := r.AudioSource.wavFormat(ctx)
+	if errors.Is(err, errNotWav) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if rc.SampleRate > 0 && uint32(rc.SampleRate) != wf.SampleRate {
+		errs = append(errs, fmt.Errorf("%w: sample rate is %d Hz in the config, but %d Hz in the wav header", ErrInvalidConfig, rc.SampleRate, wf.SampleRate))
+	}
+	if rc.Channels > 0 && uint16(rc.Channels) != wf.NumChannels {
+		errs = append(errs, fmt.Errorf("%w: channels is %d in the config, but %d in the wav header", ErrInvalidConfig, rc.Channels, wf.NumChannels))
+	}
+	if rc.SeparateChannels && wf.NumChannels < 2 {
+		errs = append(errs, fmt.Errorf("%w: separate channels requires multi-channel audio, but the wav file is mono", ErrInvalidConfig))
+	}
+	return newValidationError(errs...)
+}
+
+// duration returns the duration of WAV audio, when its header tells it.
+func (ra *RecognitionAudio) duration(ctx context.Context) (time.Duration, bool) {
+	wf, err := ra.wavFormat(ctx)
+	// streamed WAV files often leave the data size unset or at its maximum
+	if err != nil || wf.ByteRate == 0 || wf.DataSize == 0 || wf.DataSize == math.MaxUint32 {
+		return 0, false
+	}
+	return time.Duration(wf.DataSize) * time.Second / time.Duration(wf.ByteRate), true
+}
+
+// header returns a canonical 44 byte WAV header for dataSize bytes of samples in format wf.
+// Only the base fmt fields are written, so it suits PCM and IEEE float audio.
+func (wf *wavFormat) header(dataSize int64) []byte {
+	h := make([]byte, 44)
+	copy(h[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:8], uint32(36+dataSize))
+	copy(h[8:12], "WAVE")
+	copy(h[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:20], 16)
+	binary.LittleEndian.PutUint16(h[20:22], wf.AudioFormat)
+	binary.LittleEndian.PutUint16(h[22:24], wf.NumChannels)
+	binary.LittleEndian.PutUint32(h[24:28], wf.SampleRate)
+	binary.LittleEndian.PutUint32(h[28:32], wf.ByteRate)
+	binary.LittleEndian.PutUint16(h[32:34], wf.BlockAlign)
+	binary.LittleEndian.PutUint16(h[34:36], wf.BitsPerSample)
+	copy(h[36:40], "data")
+	binary.LittleEndian.PutUint32(h[40:44], uint32(dataSize))
+	return h
+}